@@ -10,8 +10,11 @@ import (
 	"math/rand"
 	"os"
 	"path/filepath"
+	"runtime"
+	"runtime/pprof"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	cassette "github.com/cassette-test/bindings/go"
@@ -33,7 +36,9 @@ type TestFilter struct {
 	Filter map[string]interface{}
 }
 
-// Generate test filters
+// Generate test filters. This is the legacy hand-written corpus, kept as the
+// fallback buildCorpus uses, whatever the preset, when a cassette has no
+// events to sample from.
 func generateTestFilters() []TestFilter {
 	filters := []TestFilter{}
 	now := time.Now().Unix()
@@ -76,6 +81,420 @@ func generateTestFilters() []TestFilter {
 	return filters
 }
 
+// corpusSample holds real values pulled from a cassette via a bounded
+// initial REQ, so generated filters actually match events instead of
+// always missing like the old random-hex approach.
+type corpusSample struct {
+	ids          []string
+	authors      []string
+	kinds        []int
+	eTags        []string
+	pTags        []string
+	aTags        []string
+	dTags        []string
+	tTags        []string
+	words        []string
+	minCreatedAt int64
+	maxCreatedAt int64
+}
+
+// sampleCassette issues a bounded REQ ("limit": sampleSize) against the
+// cassette and, from the events it returns, extracts real ids, pubkeys,
+// kinds, tag values, the created_at range, and a few content words to seed
+// realistic filters from.
+func sampleCassette(c *cassette.Cassette, sampleSize int) *corpusSample {
+	sample := &corpusSample{}
+
+	subID := "corpus-sample"
+	req := []interface{}{"REQ", subID, map[string]interface{}{"limit": sampleSize}}
+	reqBytes, _ := json.Marshal(req)
+	response, err := c.Send(string(reqBytes))
+	if err != nil || response == nil || response.IsSingle {
+		return sample
+	}
+
+	seenIDs := map[string]bool{}
+	seenAuthors := map[string]bool{}
+	seenKinds := map[int]bool{}
+	seenTags := map[string]map[string]bool{"e": {}, "p": {}, "a": {}, "d": {}, "t": {}}
+
+	for _, msg := range response.Multiple {
+		var parsed []interface{}
+		if err := json.Unmarshal([]byte(msg), &parsed); err != nil || len(parsed) < 3 {
+			continue
+		}
+		if msgType, ok := parsed[0].(string); !ok || msgType != "EVENT" {
+			continue
+		}
+		event, ok := parsed[2].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if id, ok := event["id"].(string); ok && id != "" && !seenIDs[id] {
+			seenIDs[id] = true
+			sample.ids = append(sample.ids, id)
+		}
+		if pubkey, ok := event["pubkey"].(string); ok && pubkey != "" && !seenAuthors[pubkey] {
+			seenAuthors[pubkey] = true
+			sample.authors = append(sample.authors, pubkey)
+		}
+		if kind, ok := event["kind"].(float64); ok && !seenKinds[int(kind)] {
+			seenKinds[int(kind)] = true
+			sample.kinds = append(sample.kinds, int(kind))
+		}
+		if createdAt, ok := event["created_at"].(float64); ok {
+			ts := int64(createdAt)
+			if sample.minCreatedAt == 0 || ts < sample.minCreatedAt {
+				sample.minCreatedAt = ts
+			}
+			if ts > sample.maxCreatedAt {
+				sample.maxCreatedAt = ts
+			}
+		}
+		if tags, ok := event["tags"].([]interface{}); ok {
+			for _, rawTag := range tags {
+				tag, ok := rawTag.([]interface{})
+				if !ok || len(tag) < 2 {
+					continue
+				}
+				tagName, ok := tag[0].(string)
+				if !ok {
+					continue
+				}
+				tagValue, ok := tag[1].(string)
+				if !ok {
+					continue
+				}
+				if bucket, tracked := seenTags[tagName]; tracked && !bucket[tagValue] {
+					bucket[tagValue] = true
+					switch tagName {
+					case "e":
+						sample.eTags = append(sample.eTags, tagValue)
+					case "p":
+						sample.pTags = append(sample.pTags, tagValue)
+					case "a":
+						sample.aTags = append(sample.aTags, tagValue)
+					case "d":
+						sample.dTags = append(sample.dTags, tagValue)
+					case "t":
+						sample.tTags = append(sample.tTags, tagValue)
+					}
+				}
+			}
+		}
+		if content, ok := event["content"].(string); ok && content != "" {
+			for _, word := range strings.Fields(content) {
+				if len(word) >= 4 {
+					sample.words = append(sample.words, word)
+					break
+				}
+			}
+		}
+	}
+
+	// Close the sampling subscription so it doesn't linger in the cassette's dedup state.
+	closeReq, _ := json.Marshal([]interface{}{"CLOSE", subID})
+	c.Send(string(closeReq))
+
+	return sample
+}
+
+func pickString(values []string, n int) []string {
+	if len(values) == 0 {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = generateRandomHex(64)
+		}
+		return out
+	}
+	out := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		out = append(out, values[i%len(values)])
+	}
+	return out
+}
+
+func pickInts(values []int, fallback []int) []int {
+	if len(values) == 0 {
+		return fallback
+	}
+	if len(values) > 5 {
+		values = values[:5]
+	}
+	return values
+}
+
+// corpusFromSample builds the named preset's filters from real values pulled
+// out of the cassette. micro is a minimal smoke-test set; client-typical
+// models a regular client subscribing to a handful of authors/kinds/threads;
+// relay-crawl models a backfilling crawler issuing broad, high-limit
+// queries; search-heavy exercises NIP-50 `search` filters.
+func corpusFromSample(preset string, sample *corpusSample) ([]TestFilter, error) {
+	now := time.Now().Unix()
+
+	switch preset {
+	case "micro":
+		return []TestFilter{
+			{"empty", map[string]interface{}{}},
+			{"limit_1", map[string]interface{}{"limit": 1}},
+			{"limit_10", map[string]interface{}{"limit": 10}},
+			{"kinds_1", map[string]interface{}{"kinds": pickInts(sample.kinds, []int{1})}},
+		}, nil
+
+	case "client-typical":
+		filters := []TestFilter{
+			{"ids_lookup", map[string]interface{}{"ids": pickString(sample.ids, 5)}},
+			{"author_single", map[string]interface{}{"authors": pickString(sample.authors, 1)}},
+			{"authors_5", map[string]interface{}{"authors": pickString(sample.authors, 5)}},
+			{"kinds_multiple", map[string]interface{}{"kinds": pickInts(sample.kinds, []int{1, 7, 0})}},
+			{"tag_e", map[string]interface{}{"#e": pickString(sample.eTags, 1)}},
+			{"tag_e_multi", map[string]interface{}{"#e": pickString(sample.eTags, 3)}},
+			{"tag_p", map[string]interface{}{"#p": pickString(sample.pTags, 1)}},
+			{"tag_a_multi", map[string]interface{}{"#a": pickString(sample.aTags, 3)}},
+			{"tag_d_multi", map[string]interface{}{"#d": pickString(sample.dTags, 3)}},
+			{"tag_t_multi", map[string]interface{}{"#t": pickString(sample.tTags, 3)}},
+			{"complex", map[string]interface{}{
+				"kinds":   pickInts(sample.kinds, []int{1}),
+				"limit":   50,
+				"since":   now - 86400,
+				"authors": pickString(sample.authors, 1),
+			}},
+		}
+		return filters, nil
+
+	case "relay-crawl":
+		since := sample.minCreatedAt
+		if since == 0 {
+			since = now - 30*86400
+		}
+		return []TestFilter{
+			{"limit_1000", map[string]interface{}{"limit": 1000}},
+			{"kinds_broad", map[string]interface{}{"kinds": pickInts(sample.kinds, []int{0, 1, 3, 7})}},
+			{"since_full_range", map[string]interface{}{"since": since, "limit": 500}},
+			{"time_window", map[string]interface{}{"since": since, "until": sample.maxCreatedAt, "limit": 500}},
+		}, nil
+
+	case "search-heavy":
+		words := sample.words
+		if len(words) == 0 {
+			words = []string{"nostr"}
+		}
+		filters := make([]TestFilter, 0, len(words))
+		for i, word := range words {
+			if i >= 5 {
+				break
+			}
+			filters = append(filters, TestFilter{fmt.Sprintf("search_%d", i), map[string]interface{}{"search": word}})
+		}
+		filters = append(filters, TestFilter{"search_with_kind", map[string]interface{}{
+			"search": words[0],
+			"kinds":  pickInts(sample.kinds, []int{1}),
+		}})
+		return filters, nil
+
+	default:
+		return nil, fmt.Errorf("unknown corpus preset %q (want micro, client-typical, relay-crawl, or search-heavy)", preset)
+	}
+}
+
+// filterTemplate is one entry of a -corpus-file JSON array: a named filter
+// plus a relative weight controlling how many times it's repeated in the
+// generated corpus (so a deployment's actual traffic mix can be modeled).
+type filterTemplate struct {
+	Name   string                 `json:"name"`
+	Weight float64                `json:"weight"`
+	Filter map[string]interface{} `json:"filter"`
+}
+
+func loadCorpusFile(path string) ([]TestFilter, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read corpus file %s: %w", path, err)
+	}
+
+	var templates []filterTemplate
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return nil, fmt.Errorf("failed to parse corpus file %s: %w", path, err)
+	}
+
+	var filters []TestFilter
+	for _, tmpl := range templates {
+		weight := tmpl.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		count := int(math.Round(weight))
+		if count < 1 {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			name := tmpl.Name
+			if count > 1 {
+				name = fmt.Sprintf("%s_%d", tmpl.Name, i)
+			}
+			filters = append(filters, TestFilter{name, tmpl.Filter})
+		}
+	}
+	return filters, nil
+}
+
+// buildCorpus resolves the filter corpus for a benchmark run: a -corpus-file
+// takes precedence, otherwise the named preset is built from real values
+// sampled off the cassette itself (falling back to generateTestFilters for
+// cassettes with no events to sample).
+func buildCorpus(c *cassette.Cassette, corpusName, corpusFile string) ([]TestFilter, error) {
+	if corpusFile != "" {
+		return loadCorpusFile(corpusFile)
+	}
+
+	sample := sampleCassette(c, 200)
+	if len(sample.authors) == 0 && len(sample.kinds) == 0 {
+		return generateTestFilters(), nil
+	}
+	return corpusFromSample(corpusName, sample)
+}
+
+// Histogram is a logarithmic-bucket latency histogram in the style of
+// HdrHistogram: base-2 buckets spanning 1us-~16s with ~1% sub-bucket
+// resolution. Each goroutine accumulates into its own Histogram (no locking
+// on the hot path) and callers Merge() the per-goroutine results together,
+// so p50/p95/p99/p999 stay accurate even across millions of samples without
+// retaining every individual timing.
+type Histogram struct {
+	counts []int64
+	total  int64
+}
+
+const (
+	histSubBucketsPerOctave = 128
+	histOctaves             = 24 // 1us .. ~16.7s
+	histNumBuckets          = histOctaves * histSubBucketsPerOctave
+)
+
+func newHistogram() *Histogram {
+	return &Histogram{counts: make([]int64, histNumBuckets)}
+}
+
+func histBucket(us float64) int {
+	if us < 1 {
+		us = 1
+	}
+	idx := int(math.Log2(us) * histSubBucketsPerOctave)
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= histNumBuckets {
+		idx = histNumBuckets - 1
+	}
+	return idx
+}
+
+func histBucketValueUs(idx int) float64 {
+	return math.Pow(2, float64(idx)/histSubBucketsPerOctave)
+}
+
+// Record adds a latency sample given in microseconds.
+func (h *Histogram) Record(us float64) {
+	h.counts[histBucket(us)]++
+	h.total++
+}
+
+// Merge folds other's counts into h.
+func (h *Histogram) Merge(other *Histogram) {
+	if other == nil {
+		return
+	}
+	for i, c := range other.counts {
+		h.counts[i] += c
+	}
+	h.total += other.total
+}
+
+// PercentileMs returns the p-th percentile (0-1) latency in milliseconds.
+func (h *Histogram) PercentileMs(p float64) float64 {
+	if h.total == 0 {
+		return 0
+	}
+	target := int64(math.Ceil(p * float64(h.total)))
+	if target < 1 {
+		target = 1
+	}
+	var cum int64
+	for i, c := range h.counts {
+		cum += c
+		if cum >= target {
+			return histBucketValueUs(i) / 1000.0
+		}
+	}
+	return histBucketValueUs(histNumBuckets-1) / 1000.0
+}
+
+// MeanMs returns the mean latency in milliseconds.
+func (h *Histogram) MeanMs() float64 {
+	if h.total == 0 {
+		return 0
+	}
+	var sum float64
+	for i, c := range h.counts {
+		if c == 0 {
+			continue
+		}
+		sum += histBucketValueUs(i) * float64(c)
+	}
+	return sum / float64(h.total) / 1000.0
+}
+
+// FilterStats summarizes one filter's run, whether driven serially or by
+// the concurrent/duration worker pool.
+type FilterStats struct {
+	Count          int64
+	AvgMs          float64
+	P50Ms          float64
+	P95Ms          float64
+	P99Ms          float64
+	P999Ms         float64
+	AvgEvents      float64
+	QPS            float64
+	AvgAllocsPerOp float64
+	AvgBytesPerOp  float64
+	Goroutines     int
+}
+
+// memSnapshot captures the allocation counters we diff across a filter
+// batch. TotalAlloc/Mallocs are cumulative, so (after - before) / iterations
+// gives the average bytes/allocs per Send() call for that filter.
+type memSnapshot struct {
+	mallocs uint64
+	bytes   uint64
+}
+
+func snapshotMem() memSnapshot {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return memSnapshot{mallocs: m.Mallocs, bytes: m.TotalAlloc}
+}
+
+// writeProfile writes the named pprof profile (e.g. "heap", "mutex",
+// "block") to path if path is non-empty. Called via defer so the profile is
+// captured after the run has generated data for it.
+func writeProfile(path, name string) {
+	if path == "" {
+		return
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		log.Printf("failed to create %s profile file %s: %v", name, path, err)
+		return
+	}
+	defer f.Close()
+
+	runtime.GC()
+	if err := pprof.Lookup(name).WriteTo(f, 0); err != nil {
+		log.Printf("failed to write %s profile: %v", name, err)
+	}
+}
+
 // BenchmarkResult stores benchmark results
 type BenchmarkResult struct {
 	CassetteName      string
@@ -83,6 +502,9 @@ type BenchmarkResult struct {
 	EventCount        int
 	FilterTimings     map[string][]float64
 	FilterEventCounts map[string][]int
+	FilterStats       map[string]FilterStats
+	AggregateQPS      float64
+	Concurrency       int
 }
 
 // Calculate percentile
@@ -124,8 +546,129 @@ func averageInt(values []int) float64 {
 	return float64(sum) / float64(len(values))
 }
 
+// countEvents tallies EVENT frames in a Send response.
+func countEvents(response *cassette.SendResult) int {
+	count := 0
+	if response == nil || response.IsSingle {
+		return count
+	}
+	for _, msg := range response.Multiple {
+		var parsed []interface{}
+		if err := json.Unmarshal([]byte(msg), &parsed); err == nil {
+			if len(parsed) > 0 && parsed[0] == "EVENT" {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// runFilterSerial runs a filter iterations times on the calling goroutine,
+// keeping the full per-iteration timing slice (the historical behavior).
+func runFilterSerial(c *cassette.Cassette, test TestFilter, iterations int) ([]float64, []int) {
+	times := make([]float64, 0, iterations)
+	eventCounts := make([]int, 0, iterations)
+
+	for i := 0; i < iterations; i++ {
+		if i%10 == 0 {
+			fmt.Print(".")
+		}
+
+		subId := fmt.Sprintf("bench-%s-%d", test.Name, i)
+		req := []interface{}{"REQ", subId, test.Filter}
+		reqBytes, _ := json.Marshal(req)
+
+		start := time.Now()
+		response, err := c.Send(string(reqBytes))
+		elapsed := time.Since(start).Seconds() * 1000 // Convert to milliseconds
+		if err != nil {
+			response = nil
+		}
+
+		times = append(times, elapsed)
+		eventCounts = append(eventCounts, countEvents(response))
+	}
+
+	return times, eventCounts
+}
+
+// runFilterConcurrent drives a filter with `concurrency` goroutines sharing
+// a work queue of iterations (or, in duration mode, each goroutine runs
+// until the wall-clock deadline passes instead of draining a fixed queue).
+// Latencies are accumulated into a per-goroutine Histogram and merged at the
+// end, so this scales to durations/iteration counts that would be too large
+// to keep as a raw slice.
+func runFilterConcurrent(c *cassette.Cassette, test TestFilter, iterations, concurrency int, runDuration time.Duration) (*Histogram, []int, time.Duration) {
+	var wg sync.WaitGroup
+	histos := make([]*Histogram, concurrency)
+	eventCounts := make([][]int, concurrency)
+
+	start := time.Now()
+	deadline := start.Add(runDuration)
+
+	var work chan int
+	if runDuration <= 0 {
+		work = make(chan int, concurrency)
+		go func() {
+			defer close(work)
+			for i := 0; i < iterations; i++ {
+				work <- i
+			}
+		}()
+	}
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			h := newHistogram()
+			var counts []int
+			i := 0
+			for {
+				if runDuration > 0 {
+					if time.Now().After(deadline) {
+						break
+					}
+				} else {
+					if _, ok := <-work; !ok {
+						break
+					}
+				}
+
+				subID := fmt.Sprintf("bench-%s-w%d-%d", test.Name, worker, i)
+				req := []interface{}{"REQ", subID, test.Filter}
+				reqBytes, _ := json.Marshal(req)
+
+				reqStart := time.Now()
+				response, err := c.Send(string(reqBytes))
+				elapsedUs := time.Since(reqStart).Seconds() * 1e6
+				if err != nil {
+					response = nil
+				}
+
+				h.Record(elapsedUs)
+				counts = append(counts, countEvents(response))
+				i++
+			}
+			histos[worker] = h
+			eventCounts[worker] = counts
+		}(w)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	merged := newHistogram()
+	var allCounts []int
+	for w := 0; w < concurrency; w++ {
+		merged.Merge(histos[w])
+		allCounts = append(allCounts, eventCounts[w]...)
+	}
+
+	return merged, allCounts, elapsed
+}
+
 // Benchmark a single cassette
-func benchmarkCassette(cassettePath string, iterations int) (*BenchmarkResult, error) {
+func benchmarkCassette(cassettePath string, iterations int, concurrency int, runDuration time.Duration, corpusName string, corpusFile string) (*BenchmarkResult, error) {
 	fmt.Printf("\n📼 Benchmarking: %s\n", filepath.Base(cassettePath))
 	fmt.Println(strings.Repeat("=", 60))
 
@@ -139,16 +682,21 @@ func benchmarkCassette(cassettePath string, iterations int) (*BenchmarkResult, e
 		FileSize:          fileInfo.Size(),
 		FilterTimings:     make(map[string][]float64),
 		FilterEventCounts: make(map[string][]int),
+		FilterStats:       make(map[string]FilterStats),
+		Concurrency:       concurrency,
 	}
 
 	// Load cassette
-	c, err := cassette.Load(cassettePath, false)
+	c, err := cassette.LoadCassette(cassettePath, false)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get cassette info
-	infoStr := c.Info()
+	infoStr, err := c.Info()
+	if err != nil {
+		return nil, err
+	}
 	var info map[string]interface{}
 	if err := json.Unmarshal([]byte(infoStr), &info); err != nil {
 		return nil, err
@@ -161,6 +709,12 @@ func benchmarkCassette(cassettePath string, iterations int) (*BenchmarkResult, e
 	fmt.Printf("ℹ️  Cassette: %v\n", info["name"])
 	fmt.Printf("   Events: %d\n", result.EventCount)
 	fmt.Printf("   Size: %.1f KB\n", float64(result.FileSize)/1024)
+	if concurrency > 1 {
+		fmt.Printf("   Concurrency: %d\n", concurrency)
+	}
+	if runDuration > 0 {
+		fmt.Printf("   Duration: %s per filter\n", runDuration)
+	}
 
 	// Warm up
 	fmt.Println("🔥 Warming up...")
@@ -170,54 +724,95 @@ func benchmarkCassette(cassettePath string, iterations int) (*BenchmarkResult, e
 		c.Send(string(reqBytes))
 	}
 
-	// Test filters
-	testFilters := generateTestFilters()
+	// Test filters, sampled off the cassette's own data where possible
+	testFilters, err := buildCorpus(c, corpusName, corpusFile)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("   Corpus: %d filter(s)", len(testFilters))
+	if corpusFile != "" {
+		fmt.Printf(" (from %s)\n", corpusFile)
+	} else {
+		fmt.Printf(" (%s preset)\n", corpusName)
+	}
+
+	if runDuration > 0 {
+		fmt.Printf("\n🏃 Running %s per filter across %d goroutine(s)...\n", runDuration, concurrency)
+	} else {
+		fmt.Printf("\n🏃 Running %d iterations per filter across %d goroutine(s)...\n", iterations, concurrency)
+	}
 
-	fmt.Printf("\n🏃 Running %d iterations per filter...\n", iterations)
+	var aggregateCount int64
+	var aggregateElapsed time.Duration
 
 	for idx, test := range testFilters {
 		fmt.Printf("\n  Testing filter %d/%d: %s", idx+1, len(testFilters), test.Name)
 
-		times := make([]float64, 0, iterations)
-		eventCounts := make([]int, 0, iterations)
+		var stats FilterStats
+		memBefore := snapshotMem()
+
+		if concurrency <= 1 && runDuration <= 0 {
+			times, eventCounts := runFilterSerial(c, test, iterations)
+			result.FilterTimings[test.Name] = times
+			result.FilterEventCounts[test.Name] = eventCounts
 
-		for i := 0; i < iterations; i++ {
-			if i%10 == 0 {
-				fmt.Print(".")
+			hist := newHistogram()
+			for _, t := range times {
+				hist.Record(t * 1000.0)
+			}
+			elapsed := time.Duration(0)
+			for _, t := range times {
+				elapsed += time.Duration(t * float64(time.Millisecond))
 			}
 
-			subId := fmt.Sprintf("bench-%s-%d", test.Name, i)
-			req := []interface{}{"REQ", subId, test.Filter}
-			reqBytes, _ := json.Marshal(req)
-
-			start := time.Now()
-			response := c.Send(string(reqBytes))
-			elapsed := time.Since(start).Seconds() * 1000 // Convert to milliseconds
-
-			times = append(times, elapsed)
-
-			// Count events returned
-			eventCount := 0
-			if !response.IsSingle && response.Multiple != nil {
-				for _, msg := range response.Multiple {
-					var parsed []interface{}
-					if err := json.Unmarshal([]byte(msg), &parsed); err == nil {
-						if len(parsed) > 0 && parsed[0] == "EVENT" {
-							eventCount++
-						}
-					}
-				}
+			stats = FilterStats{
+				Count:     int64(len(times)),
+				AvgMs:     average(times),
+				P50Ms:     percentile(times, 0.5),
+				P95Ms:     percentile(times, 0.95),
+				P99Ms:     percentile(times, 0.99),
+				P999Ms:    hist.PercentileMs(0.999),
+				AvgEvents: averageInt(eventCounts),
 			}
-			eventCounts = append(eventCounts, eventCount)
+			if elapsed > 0 {
+				stats.QPS = float64(len(times)) / elapsed.Seconds()
+			}
+			aggregateCount += stats.Count
+			aggregateElapsed += elapsed
+		} else {
+			hist, eventCounts, elapsed := runFilterConcurrent(c, test, iterations, concurrency, runDuration)
+			result.FilterEventCounts[test.Name] = eventCounts
+
+			stats = FilterStats{
+				Count:     hist.total,
+				AvgMs:     hist.MeanMs(),
+				P50Ms:     hist.PercentileMs(0.5),
+				P95Ms:     hist.PercentileMs(0.95),
+				P99Ms:     hist.PercentileMs(0.99),
+				P999Ms:    hist.PercentileMs(0.999),
+				AvgEvents: averageInt(eventCounts),
+			}
+			if elapsed > 0 {
+				stats.QPS = float64(hist.total) / elapsed.Seconds()
+			}
+			aggregateCount += stats.Count
+			aggregateElapsed += elapsed
 		}
 
-		result.FilterTimings[test.Name] = times
-		result.FilterEventCounts[test.Name] = eventCounts
+		memAfter := snapshotMem()
+		if stats.Count > 0 {
+			stats.AvgAllocsPerOp = float64(memAfter.mallocs-memBefore.mallocs) / float64(stats.Count)
+			stats.AvgBytesPerOp = float64(memAfter.bytes-memBefore.bytes) / float64(stats.Count)
+		}
+		stats.Goroutines = runtime.NumGoroutine()
 
-		avgMs := average(times)
-		avgEvents := averageInt(eventCounts)
+		result.FilterStats[test.Name] = stats
+		fmt.Printf(" ✓ (%.1fms avg, %.0f events, %.0f qps, %.0f allocs/op, %.0f B/op)\n",
+			stats.AvgMs, stats.AvgEvents, stats.QPS, stats.AvgAllocsPerOp, stats.AvgBytesPerOp)
+	}
 
-		fmt.Printf(" ✓ (%.1fms avg, %.0f events)\n", avgMs, avgEvents)
+	if aggregateElapsed > 0 {
+		result.AggregateQPS = float64(aggregateCount) / aggregateElapsed.Seconds()
 	}
 
 	return result, nil
@@ -234,7 +829,7 @@ func printComparisonTable(results []*BenchmarkResult) {
 	// Collect all filter names
 	filterSet := make(map[string]bool)
 	for _, result := range results {
-		for filterName := range result.FilterTimings {
+		for filterName := range result.FilterStats {
 			filterSet[filterName] = true
 		}
 	}
@@ -261,9 +856,23 @@ func printComparisonTable(results []*BenchmarkResult) {
 	for _, filterName := range filterNames {
 		fmt.Printf("%-20s", filterName)
 		for _, result := range results {
-			if times, ok := result.FilterTimings[filterName]; ok && len(times) > 0 {
-				avgMs := average(times)
-				fmt.Printf("%11.2f  ", avgMs)
+			if stats, ok := result.FilterStats[filterName]; ok && stats.Count > 0 {
+				fmt.Printf("%11.2f  ", stats.AvgMs)
+			} else {
+				fmt.Printf("%11s  ", "N/A")
+			}
+		}
+		fmt.Println()
+	}
+
+	// Throughput table
+	fmt.Println("\n⚡ REQ THROUGHPUT (queries/sec)")
+	fmt.Println(strings.Repeat("=", 100))
+	for _, filterName := range filterNames {
+		fmt.Printf("%-20s", filterName)
+		for _, result := range results {
+			if stats, ok := result.FilterStats[filterName]; ok && stats.Count > 0 {
+				fmt.Printf("%11.0f  ", stats.QPS)
 			} else {
 				fmt.Printf("%11s  ", "N/A")
 			}
@@ -274,38 +883,105 @@ func printComparisonTable(results []*BenchmarkResult) {
 	// Summary stats
 	fmt.Println("\n📈 SUMMARY STATISTICS")
 	fmt.Println(strings.Repeat("=", 100))
-	fmt.Printf("%-30s %10s %10s %10s %10s\n", "Cassette", "Size (KB)", "Events", "Avg (ms)", "P95 (ms)")
-	fmt.Println(strings.Repeat("-", 70))
+	fmt.Printf("%-30s %10s %10s %10s %10s %12s\n", "Cassette", "Size (KB)", "Events", "Avg (ms)", "P95 (ms)", "Agg QPS")
+	fmt.Println(strings.Repeat("-", 85))
 
 	for _, result := range results {
-		allTimes := []float64{}
-		for _, times := range result.FilterTimings {
-			allTimes = append(allTimes, times...)
+		var weightedSum, totalCount float64
+		var allP95 []float64
+		for _, stats := range result.FilterStats {
+			weightedSum += stats.AvgMs * float64(stats.Count)
+			totalCount += float64(stats.Count)
+			allP95 = append(allP95, stats.P95Ms)
 		}
 
-		if len(allTimes) > 0 {
-			avgTime := average(allTimes)
-			p95 := percentile(allTimes, 0.95)
+		if totalCount > 0 {
+			avgTime := weightedSum / totalCount
+			p95 := percentile(allP95, 0.95)
 
-			fmt.Printf("%-30s %10.1f %10d %10.2f %10.2f\n",
+			fmt.Printf("%-30s %10.1f %10d %10.2f %10.2f %12.0f\n",
 				result.CassetteName,
 				float64(result.FileSize)/1024,
 				result.EventCount,
 				avgTime,
-				p95)
+				p95,
+				result.AggregateQPS)
 		}
 	}
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "compare" {
+		runCompare(os.Args[2:])
+		return
+	}
+
 	var iterations int
+	var concurrency int
+	var durationStr string
+	var rawSamples bool
+	var corpusName string
+	var corpusFile string
+	var cpuProfilePath string
+	var memProfilePath string
+	var mutexProfilePath string
+	var blockProfilePath string
 	flag.IntVar(&iterations, "iterations", 100, "Number of iterations per test")
 	flag.IntVar(&iterations, "i", 100, "Number of iterations per test (shorthand)")
+	flag.IntVar(&concurrency, "concurrency", 1, "Number of goroutines issuing REQs concurrently per filter")
+	flag.StringVar(&durationStr, "duration", "", "Run each filter for a wall-clock duration (e.g. 30s) instead of a fixed iteration count")
+	flag.BoolVar(&rawSamples, "raw-samples", false, "Include raw per-iteration timings in the JSON output (only collected in serial mode; needed by the compare subcommand)")
+	flag.StringVar(&corpusName, "corpus", "client-typical", "Filter corpus preset: micro, client-typical, relay-crawl, or search-heavy")
+	flag.StringVar(&corpusFile, "corpus-file", "", "JSON array of {name, weight, filter} templates to use instead of a preset")
+	flag.StringVar(&cpuProfilePath, "cpuprofile", "", "Write a CPU pprof profile to this path")
+	flag.StringVar(&memProfilePath, "memprofile", "", "Write a heap pprof profile to this path")
+	flag.StringVar(&mutexProfilePath, "mutexprofile", "", "Write a mutex contention pprof profile to this path")
+	flag.StringVar(&blockProfilePath, "blockprofile", "", "Write a goroutine blocking pprof profile to this path")
 	flag.Parse()
 
+	if cpuProfilePath != "" {
+		f, err := os.Create(cpuProfilePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to create -cpuprofile: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		if err := pprof.StartCPUProfile(f); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to start CPU profile: %v\n", err)
+			os.Exit(1)
+		}
+		defer pprof.StopCPUProfile()
+	}
+	if mutexProfilePath != "" {
+		runtime.SetMutexProfileFraction(1)
+	}
+	if blockProfilePath != "" {
+		runtime.SetBlockProfileRate(1)
+	}
+	defer writeProfile(memProfilePath, "heap")
+	defer writeProfile(mutexProfilePath, "mutex")
+	defer writeProfile(blockProfilePath, "block")
+
+	var runDuration time.Duration
+	if durationStr != "" {
+		d, err := time.ParseDuration(durationStr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid -duration %q: %v\n", durationStr, err)
+			os.Exit(1)
+		}
+		runDuration = d
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if rawSamples && (concurrency > 1 || runDuration > 0) {
+		fmt.Fprintln(os.Stderr, "-raw-samples is only collected in serial mode and can't be combined with -concurrency or -duration")
+		os.Exit(1)
+	}
+
 	cassettePaths := flag.Args()
 	if len(cassettePaths) == 0 {
-		fmt.Fprintf(os.Stderr, "Usage: %s [--iterations N] <cassette.wasm> [cassette2.wasm ...]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Usage: %s [--iterations N] [--concurrency N] [--duration 30s] [--corpus NAME] [--corpus-file FILE] <cassette.wasm> [cassette2.wasm ...]\n", os.Args[0])
 		os.Exit(1)
 	}
 
@@ -314,7 +990,12 @@ func main() {
 
 	fmt.Println("🚀 Cassette WASM Benchmark (Go)")
 	fmt.Printf("   Cassettes: %d\n", len(cassettePaths))
-	fmt.Printf("   Iterations: %d\n", iterations)
+	if runDuration > 0 {
+		fmt.Printf("   Duration: %s\n", runDuration)
+	} else {
+		fmt.Printf("   Iterations: %d\n", iterations)
+	}
+	fmt.Printf("   Concurrency: %d\n", concurrency)
 
 	results := []*BenchmarkResult{}
 
@@ -324,7 +1005,7 @@ func main() {
 			continue
 		}
 
-		result, err := benchmarkCassette(path, iterations)
+		result, err := benchmarkCassette(path, iterations, concurrency, runDuration, corpusName, corpusFile)
 		if err != nil {
 			fmt.Printf("❌ Error with %s: %v\n", path, err)
 			continue
@@ -338,56 +1019,43 @@ func main() {
 
 		// Save results to JSON
 		output := map[string]interface{}{
-			"timestamp":  time.Now().Unix(),
-			"iterations": iterations,
-			"results":    []map[string]interface{}{},
+			"timestamp":   time.Now().Unix(),
+			"iterations":  iterations,
+			"concurrency": concurrency,
+			"duration":    durationStr,
+			"results":     []map[string]interface{}{},
 		}
 
 		for _, result := range results {
 			cassResult := map[string]interface{}{
-				"cassette":    result.CassetteName,
-				"file_size":   result.FileSize,
-				"event_count": result.EventCount,
-				"filters":     map[string]interface{}{},
+				"cassette":      result.CassetteName,
+				"file_size":     result.FileSize,
+				"event_count":   result.EventCount,
+				"aggregate_qps": result.AggregateQPS,
+				"filters":       map[string]interface{}{},
 			}
 
 			filters := cassResult["filters"].(map[string]interface{})
-			for filterName, times := range result.FilterTimings {
-				if len(times) > 0 {
-					minTime := times[0]
-					maxTime := times[0]
-					for _, t := range times {
-						if t < minTime {
-							minTime = t
-						}
-						if t > maxTime {
-							maxTime = t
-						}
-					}
-
-					filterData := map[string]interface{}{
-						"count":  len(times),
-						"avg_ms": average(times),
-						"min_ms": minTime,
-						"max_ms": maxTime,
-						"p50_ms": percentile(times, 0.5),
-						"p95_ms": percentile(times, 0.95),
-						"p99_ms": percentile(times, 0.99),
-					}
-
-					if eventCounts, ok := result.FilterEventCounts[filterName]; ok && len(eventCounts) > 0 {
-						filterData["avg_events"] = averageInt(eventCounts)
-						maxEvents := eventCounts[0]
-						for _, e := range eventCounts {
-							if e > maxEvents {
-								maxEvents = e
-							}
-						}
-						filterData["max_events"] = maxEvents
+			for filterName, stats := range result.FilterStats {
+				filterData := map[string]interface{}{
+					"count":      stats.Count,
+					"avg_ms":     stats.AvgMs,
+					"p50_ms":     stats.P50Ms,
+					"p95_ms":     stats.P95Ms,
+					"p99_ms":     stats.P99Ms,
+					"p999_ms":    stats.P999Ms,
+					"avg_events": stats.AvgEvents,
+					"qps":        stats.QPS,
+					"avg_allocs": stats.AvgAllocsPerOp,
+					"avg_bytes":  stats.AvgBytesPerOp,
+					"goroutines": stats.Goroutines,
+				}
+				if rawSamples {
+					if samples, ok := result.FilterTimings[filterName]; ok && len(samples) > 0 {
+						filterData["samples"] = samples
 					}
-
-					filters[filterName] = filterData
 				}
+				filters[filterName] = filterData
 			}
 
 			output["results"] = append(output["results"].([]map[string]interface{}), cassResult)
@@ -401,4 +1069,358 @@ func main() {
 			fmt.Printf("\n💾 Results saved to: %s\n", outputFilename)
 		}
 	}
-}
\ No newline at end of file
+}
+
+// filterFileStats is one filter's stats as read back from a benchmark_go_*.json file.
+type filterFileStats struct {
+	Count   int
+	AvgMs   float64
+	Samples []float64 // only present if the run was captured with -raw-samples
+}
+
+// benchmarkFileResult is one cassette's results as read back from a benchmark_go_*.json file.
+type benchmarkFileResult struct {
+	Cassette string
+	Filters  map[string]filterFileStats
+}
+
+func loadBenchmarkFile(path string) ([]benchmarkFileResult, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var doc struct {
+		Results []struct {
+			Cassette string                   `json:"cassette"`
+			Filters  map[string]map[string]interface{} `json:"filters"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	results := make([]benchmarkFileResult, 0, len(doc.Results))
+	for _, r := range doc.Results {
+		fr := benchmarkFileResult{Cassette: r.Cassette, Filters: make(map[string]filterFileStats)}
+		for name, raw := range r.Filters {
+			stats := filterFileStats{}
+			if c, ok := raw["count"].(float64); ok {
+				stats.Count = int(c)
+			}
+			if avg, ok := raw["avg_ms"].(float64); ok {
+				stats.AvgMs = avg
+			}
+			if samples, ok := raw["samples"].([]interface{}); ok {
+				stats.Samples = make([]float64, 0, len(samples))
+				for _, s := range samples {
+					if v, ok := s.(float64); ok {
+						stats.Samples = append(stats.Samples, v)
+					}
+				}
+			}
+			fr.Filters[name] = stats
+		}
+		results = append(results, fr)
+	}
+	return results, nil
+}
+
+// meanAndVariance returns the sample mean and unbiased sample variance.
+func meanAndVariance(values []float64) (mean, variance float64) {
+	n := len(values)
+	if n == 0 {
+		return 0, 0
+	}
+	mean = average(values)
+	if n < 2 {
+		return mean, 0
+	}
+	var sumSq float64
+	for _, v := range values {
+		d := v - mean
+		sumSq += d * d
+	}
+	variance = sumSq / float64(n-1)
+	return mean, variance
+}
+
+func lgamma(x float64) float64 {
+	v, _ := math.Lgamma(x)
+	return v
+}
+
+// betacf evaluates the continued fraction for the incomplete beta function
+// (Numerical Recipes' betacf).
+func betacf(x, a, b float64) float64 {
+	const maxIter = 200
+	const eps = 3e-12
+	const fpmin = 1e-300
+
+	qab := a + b
+	qap := a + 1
+	qam := a - 1
+	c := 1.0
+	d := 1 - qab*x/qap
+	if math.Abs(d) < fpmin {
+		d = fpmin
+	}
+	d = 1 / d
+	h := d
+
+	for m := 1; m <= maxIter; m++ {
+		m2 := float64(2 * m)
+		aa := float64(m) * (b - float64(m)) * x / ((qam + m2) * (a + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < fpmin {
+			d = fpmin
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < fpmin {
+			c = fpmin
+		}
+		d = 1 / d
+		h *= d * c
+
+		aa = -(a + float64(m)) * (qab + float64(m)) * x / ((a + m2) * (qap + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < fpmin {
+			d = fpmin
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < fpmin {
+			c = fpmin
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < eps {
+			break
+		}
+	}
+	return h
+}
+
+// incompleteBeta returns the regularized incomplete beta function I_x(a, b).
+func incompleteBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	lbeta := lgamma(a+b) - lgamma(a) - lgamma(b) + a*math.Log(x) + b*math.Log(1-x)
+	bt := math.Exp(lbeta)
+	if x < (a+1)/(a+b+2) {
+		return bt * betacf(x, a, b) / a
+	}
+	return 1 - bt*betacf(1-x, b, a)/b
+}
+
+// studentTCDF returns P(T <= t) for a Student's t distribution with df degrees of freedom.
+func studentTCDF(t, df float64) float64 {
+	x := df / (df + t*t)
+	ib := incompleteBeta(x, df/2, 0.5)
+	if t > 0 {
+		return 1 - 0.5*ib
+	}
+	return 0.5 * ib
+}
+
+// welchTTest runs Welch's t-test for two independent samples with unequal
+// variance, returning the t statistic, the Welch-Satterthwaite degrees of
+// freedom, and the two-tailed p-value.
+func welchTTest(mean1, var1 float64, n1 int, mean2, var2 float64, n2 int) (t, df, p float64) {
+	se2 := var1/float64(n1) + var2/float64(n2)
+	if se2 <= 0 {
+		return 0, 0, 1
+	}
+	t = (mean1 - mean2) / math.Sqrt(se2)
+
+	num := se2 * se2
+	den := (var1/float64(n1))*(var1/float64(n1))/float64(n1-1) + (var2/float64(n2))*(var2/float64(n2))/float64(n2-1)
+	if n1 < 2 || n2 < 2 || den == 0 {
+		df = float64(n1 + n2 - 2)
+	} else {
+		df = num / den
+	}
+
+	p = 2 * (1 - studentTCDF(math.Abs(t), df))
+	return t, df, p
+}
+
+func normalCDF(z float64) float64 {
+	return 0.5 * (1 + math.Erf(z/math.Sqrt2))
+}
+
+// mannWhitneyU computes the Mann-Whitney U statistic for two independent
+// samples and its p-value via the large-sample normal approximation
+// (mean n1*n2/2, stddev sqrt(n1*n2*(n1+n2+1)/12)).
+func mannWhitneyU(a, b []float64) (u, p float64) {
+	n1, n2 := len(a), len(b)
+	if n1 == 0 || n2 == 0 {
+		return 0, 1
+	}
+
+	type sample struct {
+		value float64
+		group int
+	}
+	merged := make([]sample, 0, n1+n2)
+	for _, v := range a {
+		merged = append(merged, sample{v, 0})
+	}
+	for _, v := range b {
+		merged = append(merged, sample{v, 1})
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].value < merged[j].value })
+
+	ranks := make([]float64, len(merged))
+	for i := 0; i < len(merged); {
+		j := i
+		for j < len(merged) && merged[j].value == merged[i].value {
+			j++
+		}
+		avgRank := float64(i+j+1) / 2.0
+		for k := i; k < j; k++ {
+			ranks[k] = avgRank
+		}
+		i = j
+	}
+
+	var r1 float64
+	for i, s := range merged {
+		if s.group == 0 {
+			r1 += ranks[i]
+		}
+	}
+
+	u1 := r1 - float64(n1*(n1+1))/2
+	u2 := float64(n1*n2) - u1
+	u = math.Min(u1, u2)
+
+	meanU := float64(n1*n2) / 2
+	stdU := math.Sqrt(float64(n1*n2*(n1+n2+1)) / 12)
+	if stdU == 0 {
+		return u, 1
+	}
+	z := (u - meanU) / stdU
+	p = 2 * (1 - normalCDF(math.Abs(z)))
+	return u, p
+}
+
+const (
+	ansiRed   = "\033[31m"
+	ansiGreen = "\033[32m"
+	ansiReset = "\033[0m"
+)
+
+// runCompare implements the `compare` subcommand: it loads two
+// benchmark_go_*.json result files and, per filter shared by both, reports
+// the mean delta plus, when both runs carry raw samples, the stddev delta
+// and a Welch's t-test and Mann-Whitney U p-value.
+// It exits nonzero if any filter regresses beyond -threshold percent with
+// p<0.05, so CI can gate cassette runtime changes on measured performance.
+func runCompare(args []string) {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	threshold := fs.Float64("threshold", 5.0, "percentage regression (candidate slower than baseline) required to fail the comparison")
+	fs.Parse(args)
+
+	rest := fs.Args()
+	if len(rest) != 2 {
+		fmt.Fprintf(os.Stderr, "Usage: %s compare [-threshold 5.0] <baseline.json> <candidate.json>\n", os.Args[0])
+		os.Exit(1)
+	}
+
+	baseline, err := loadBenchmarkFile(rest[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+	candidate, err := loadBenchmarkFile(rest[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	candidateByName := make(map[string]benchmarkFileResult)
+	for _, r := range candidate {
+		candidateByName[r.Cassette] = r
+	}
+
+	fmt.Println("📊 BENCHMARK COMPARISON")
+	fmt.Println(strings.Repeat("=", 100))
+
+	regressed := false
+
+	for _, base := range baseline {
+		cand, ok := candidateByName[base.Cassette]
+		if !ok {
+			continue
+		}
+
+		fmt.Printf("\n📼 %s\n", base.Cassette)
+		fmt.Printf("%-20s %10s %10s %10s %10s %10s %10s\n", "Filter", "Base(ms)", "Cand(ms)", "Delta%", "SD Delta%", "t-test p", "MWU p")
+		fmt.Println(strings.Repeat("-", 86))
+
+		filterNames := make([]string, 0, len(base.Filters))
+		for name := range base.Filters {
+			filterNames = append(filterNames, name)
+		}
+		sort.Strings(filterNames)
+
+		for _, name := range filterNames {
+			baseStats, ok := base.Filters[name]
+			if !ok {
+				continue
+			}
+			candStats, ok := cand.Filters[name]
+			if !ok {
+				continue
+			}
+
+			deltaPct := 0.0
+			if baseStats.AvgMs > 0 {
+				deltaPct = (candStats.AvgMs - baseStats.AvgMs) / baseStats.AvgMs * 100
+			}
+
+			color := ansiGreen
+			if deltaPct > 0 {
+				color = ansiRed
+			}
+
+			tStr, mwuStr, sdStr := "n/a", "n/a", "n/a"
+			significant := false
+
+			if len(baseStats.Samples) >= 2 && len(candStats.Samples) >= 2 {
+				baseMean, baseVar := meanAndVariance(baseStats.Samples)
+				candMean, candVar := meanAndVariance(candStats.Samples)
+				if baseVar > 0 {
+					baseSD := math.Sqrt(baseVar)
+					sdStr = fmt.Sprintf("%.1f%%", (math.Sqrt(candVar)-baseSD)/baseSD*100)
+				}
+				_, _, tP := welchTTest(candMean, candVar, len(candStats.Samples), baseMean, baseVar, len(baseStats.Samples))
+				_, mwuP := mannWhitneyU(baseStats.Samples, candStats.Samples)
+
+				tStr = fmt.Sprintf("%.4f", tP)
+				mwuStr = fmt.Sprintf("%.4f", mwuP)
+				significant = tP < 0.05
+			}
+
+			fmt.Printf("%-20s %10.2f %10.2f %s%9.1f%%%s %10s %10s %10s", name, baseStats.AvgMs, candStats.AvgMs, color, deltaPct, ansiReset, sdStr, tStr, mwuStr)
+
+			if deltaPct > *threshold && significant {
+				fmt.Print("  ⚠️  REGRESSION")
+				regressed = true
+			}
+			fmt.Println()
+		}
+	}
+
+	fmt.Println()
+	if regressed {
+		fmt.Printf("❌ One or more filters regressed beyond %.1f%% with p<0.05\n", *threshold)
+		os.Exit(1)
+	}
+	fmt.Println("✅ No statistically significant regressions detected")
+}
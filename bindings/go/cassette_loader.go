@@ -1,10 +1,13 @@
 package cassette
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/bytecodealliance/wasmtime-go/v23"
 )
@@ -16,39 +19,173 @@ type SendResult struct {
 	Multiple []string
 }
 
-// EventTracker manages event deduplication
-type EventTracker struct {
-	mu         sync.Mutex
-	eventIDs   map[string]bool
-	subscripID string
+// ErrCassetteTimeout is returned when a WASM call (Send/Info/Describe) is
+// interrupted because its deadline elapsed or its context was cancelled
+// before the guest returned.
+var ErrCassetteTimeout = errors.New("cassette: call timed out")
+
+// defaultEpochTickInterval is how often a Cassette's background ticker bumps
+// its engine's epoch counter. wasmtime compares this counter against each
+// Store's epoch deadline to decide when to trap a runaway guest call, so
+// this interval is also the finest granularity at which CallTimeout can be
+// enforced.
+const defaultEpochTickInterval = 10 * time.Millisecond
+
+// noEpochDeadlineTicks arms a Store's epoch deadline far enough in the
+// future to be effectively infinite, since wasmtime traps on a Store's very
+// first epoch check if no deadline was ever set (the zero value), and a
+// call whose ctx carries no deadline still needs one armed.
+const noEpochDeadlineTicks = 1 << 62
+
+// CassetteOptions configures optional behavior for LoadCassetteWithOptions
+// and LoadCassettePool.
+type CassetteOptions struct {
+	// CallTimeout bounds how long any single WASM call (Send/Info/Describe)
+	// may run before it's interrupted via wasmtime epoch deadlines. Zero
+	// disables the default timeout; the *Context variants of those calls
+	// still honor ctx's own deadline/cancellation regardless of this value.
+	CallTimeout time.Duration
 }
 
-// NewEventTracker creates a new event tracker
-func NewEventTracker() *EventTracker {
-	return &EventTracker{
-		eventIDs: make(map[string]bool),
-	}
+// newEngineWithEpochInterruption creates a wasmtime Engine configured so its
+// Stores can be interrupted mid-call once their epoch deadline is reached.
+func newEngineWithEpochInterruption() *wasmtime.Engine {
+	cfg := wasmtime.NewConfig()
+	cfg.SetEpochInterruption(true)
+	return wasmtime.NewEngineWithConfig(cfg)
 }
 
-// Reset clears the event tracker
-func (et *EventTracker) Reset() {
-	et.mu.Lock()
-	defer et.mu.Unlock()
-	et.eventIDs = make(map[string]bool)
+// startEpochTicker launches a background goroutine that increments engine's
+// epoch counter on a fixed interval, driving epoch-deadline interruption for
+// every Store built on it. Call the returned stop function to end it.
+func startEpochTicker(engine *wasmtime.Engine) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(defaultEpochTickInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				engine.IncrementEpoch()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
 }
 
-// AddAndCheck adds an event ID and returns true if it's new
-func (et *EventTracker) AddAndCheck(eventID string) bool {
-	et.mu.Lock()
-	defer et.mu.Unlock()
-	
-	if et.eventIDs[eventID] {
+// defaultMaxEventsPerSubscription bounds how many event IDs a single
+// subscription's dedup tracker retains before evicting the oldest ones, so a
+// long-lived REQ doesn't grow its tracker unboundedly.
+const defaultMaxEventsPerSubscription = 100000
+
+// subscriptionTracker holds the dedup state for a single subscription ID,
+// with FIFO eviction once maxEvents is reached.
+type subscriptionTracker struct {
+	eventIDs  map[string]bool
+	order     []string
+	maxEvents int
+}
+
+func newSubscriptionTracker(maxEvents int) *subscriptionTracker {
+	return &subscriptionTracker{
+		eventIDs:  make(map[string]bool),
+		maxEvents: maxEvents,
+	}
+}
+
+// addAndCheck adds an event ID and returns true if it's new.
+func (t *subscriptionTracker) addAndCheck(eventID string) bool {
+	if t.eventIDs[eventID] {
 		return false
 	}
-	et.eventIDs[eventID] = true
+
+	if t.maxEvents > 0 && len(t.order) >= t.maxEvents {
+		oldest := t.order[0]
+		t.order = t.order[1:]
+		delete(t.eventIDs, oldest)
+	}
+
+	t.eventIDs[eventID] = true
+	t.order = append(t.order, eventID)
 	return true
 }
 
+// SubscriptionRegistry replaces a single shared dedup map with one tracker
+// per subscription ID, so that opening or closing one subscription no
+// longer wipes the dedup state of every other subscription multiplexed over
+// the same cassette. Trackers are allocated on REQ and freed on
+// CLOSE/CLOSED, and optionally on EOSE as well (see closeOnEOSE).
+type SubscriptionRegistry struct {
+	mu          sync.Mutex
+	subs        map[string]*subscriptionTracker
+	maxEvents   int
+	closeOnEOSE bool
+}
+
+// NewSubscriptionRegistry creates a registry. maxEvents bounds each
+// subscription's tracker (0 means unbounded); closeOnEOSE controls whether
+// EOSE also frees the tracker, in addition to CLOSE/CLOSED.
+func NewSubscriptionRegistry(maxEvents int, closeOnEOSE bool) *SubscriptionRegistry {
+	return &SubscriptionRegistry{
+		subs:        make(map[string]*subscriptionTracker),
+		maxEvents:   maxEvents,
+		closeOnEOSE: closeOnEOSE,
+	}
+}
+
+// Open allocates fresh dedup state for a subscription ID, called when a REQ
+// for that ID is seen.
+func (r *SubscriptionRegistry) Open(subID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subs[subID] = newSubscriptionTracker(r.maxEvents)
+}
+
+// Close frees a subscription's dedup state, called on CLOSE/CLOSED and
+// (when closeOnEOSE is set) on EOSE.
+func (r *SubscriptionRegistry) Close(subID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.subs, subID)
+}
+
+// CloseOnTerminalFrame frees subID's tracker if frameType warrants it
+// (CLOSE/CLOSED always; EOSE only when the registry was configured with
+// closeOnEOSE).
+func (r *SubscriptionRegistry) CloseOnTerminalFrame(subID, frameType string) {
+	switch frameType {
+	case "CLOSE", "CLOSED":
+		r.Close(subID)
+	case "EOSE":
+		if r.closeOnEOSE {
+			r.Close(subID)
+		}
+	}
+}
+
+// AddAndCheck adds an event ID to subID's tracker (allocating one on first
+// use if REQ wasn't observed) and returns true if it's new.
+func (r *SubscriptionRegistry) AddAndCheck(subID, eventID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tracker, ok := r.subs[subID]
+	if !ok {
+		tracker = newSubscriptionTracker(r.maxEvents)
+		r.subs[subID] = tracker
+	}
+	return tracker.addAndCheck(eventID)
+}
+
+// ResetAll drops every subscription's dedup state.
+func (r *SubscriptionRegistry) ResetAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subs = make(map[string]*subscriptionTracker)
+}
+
 // MemoryManager handles WASM memory operations
 type MemoryManager struct {
 	memory    *wasmtime.Memory
@@ -129,25 +266,67 @@ func (mm *MemoryManager) ReadString(ptr int32) (string, error) {
 
 // Cassette represents a loaded cassette
 type Cassette struct {
-	engine       *wasmtime.Engine
-	store        *wasmtime.Store
-	instance     *wasmtime.Instance
-	memory       *MemoryManager
-	eventTracker *EventTracker
-	exports      map[string]*wasmtime.Func
-	debug        bool
-	mu           sync.Mutex
+	engine        *wasmtime.Engine
+	store         *wasmtime.Store
+	instance      *wasmtime.Instance
+	memory        *MemoryManager
+	subscriptions *SubscriptionRegistry
+	exports       map[string]*wasmtime.Func
+	debug         bool
+	mu            sync.Mutex
+	callTimeout   time.Duration
+	epochStop     func()
+	alias         string
+	aliasMeta     map[string]string
 }
 
-// LoadCassette loads a cassette from a WASM file
-func LoadCassette(path string, debug bool) (*Cassette, error) {
-	engine := wasmtime.NewEngine()
-	module, err := wasmtime.NewModuleFromFile(engine, path)
+// mergeAliasMetadata overlays alias metadata (set via LoadCassetteByAlias)
+// onto a NIP-11-shaped JSON document: a "name" entry in the metadata fills
+// in a missing top-level "name", and the full alias plus its metadata are
+// copied in under an "alias" key so operators can tell which friendly name
+// resolved to this cassette. Returns docStr unchanged if there's no alias,
+// or if docStr isn't a JSON object.
+func (c *Cassette) mergeAliasMetadata(docStr string) string {
+	if c.alias == "" && len(c.aliasMeta) == 0 {
+		return docStr
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(docStr), &doc); err != nil || doc == nil {
+		return docStr
+	}
+
+	if name, ok := c.aliasMeta["name"]; ok && name != "" {
+		if _, hasName := doc["name"]; !hasName {
+			doc["name"] = name
+		}
+	}
+
+	aliasInfo := map[string]interface{}{"alias": c.alias}
+	for k, v := range c.aliasMeta {
+		aliasInfo[k] = v
+	}
+	doc["alias"] = aliasInfo
+
+	merged, err := json.Marshal(doc)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load module: %w", err)
+		return docStr
 	}
+	return string(merged)
+}
 
+// newCassetteInstance instantiates module into a fresh Store on engine and
+// wires up its memory manager and exports. Pass a non-nil subscriptions to
+// reuse an existing dedup registry (as CassettePool does for shared-dedup
+// workers); pass nil to allocate a private one.
+func newCassetteInstance(engine *wasmtime.Engine, module *wasmtime.Module, debug bool, subscriptions *SubscriptionRegistry, opts CassetteOptions) (*Cassette, error) {
 	store := wasmtime.NewStore(engine)
+	// A fresh Store's epoch deadline defaults to zero, which traps on the
+	// very first epoch check - arm an effectively-infinite one up front so
+	// calls that reach into WASM outside callWithDeadline (e.g. alloc_string
+	// from WriteString) don't spuriously fail before any real deadline is
+	// ever set.
+	store.SetEpochDeadline(noEpochDeadlineTicks)
 	instance, err := wasmtime.NewInstance(store, module, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to instantiate module: %w", err)
@@ -162,7 +341,7 @@ func LoadCassette(path string, debug bool) (*Cassette, error) {
 	exports := make(map[string]*wasmtime.Func)
 	requiredFuncs := []string{"send", "info", "dealloc_string"}
 	optionalFuncs := []string{"describe"}
-	
+
 	for _, name := range requiredFuncs {
 		fn := instance.GetFunc(store, name)
 		if fn == nil && name != "dealloc_string" {
@@ -172,7 +351,7 @@ func LoadCassette(path string, debug bool) (*Cassette, error) {
 			exports[name] = fn
 		}
 	}
-	
+
 	for _, name := range optionalFuncs {
 		fn := instance.GetFunc(store, name)
 		if fn != nil {
@@ -180,26 +359,73 @@ func LoadCassette(path string, debug bool) (*Cassette, error) {
 		}
 	}
 
+	if subscriptions == nil {
+		subscriptions = NewSubscriptionRegistry(defaultMaxEventsPerSubscription, true)
+	}
+
 	return &Cassette{
-		engine:       engine,
-		store:        store,
-		instance:     instance,
-		memory:       memMgr,
-		eventTracker: NewEventTracker(),
-		exports:      exports,
-		debug:        debug,
+		engine:        engine,
+		store:         store,
+		instance:      instance,
+		memory:        memMgr,
+		subscriptions: subscriptions,
+		exports:       exports,
+		debug:         debug,
+		callTimeout:   opts.CallTimeout,
 	}, nil
 }
 
-// Describe returns the cassette description
+// LoadCassette loads a cassette from a WASM file
+func LoadCassette(path string, debug bool) (*Cassette, error) {
+	return LoadCassetteWithOptions(path, debug, CassetteOptions{})
+}
+
+// LoadCassetteWithOptions is like LoadCassette but accepts CassetteOptions,
+// currently just CallTimeout, to bound individual WASM calls. The returned
+// Cassette's engine runs epoch interruption so a malformed or adversarial
+// cassette can't hang the call indefinitely.
+func LoadCassetteWithOptions(path string, debug bool, opts CassetteOptions) (*Cassette, error) {
+	engine := newEngineWithEpochInterruption()
+	module, err := wasmtime.NewModuleFromFile(engine, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load module: %w", err)
+	}
+
+	c, err := newCassetteInstance(engine, module, debug, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	c.epochStop = startEpochTicker(engine)
+	return c, nil
+}
+
+// Close stops the cassette's background epoch ticker. It's safe to call
+// more than once.
+func (c *Cassette) Close() {
+	if c.epochStop != nil {
+		c.epochStop()
+		c.epochStop = nil
+	}
+}
+
+// Describe returns the cassette description, bounded only by the cassette's
+// configured CallTimeout (if any).
 func (c *Cassette) Describe() (string, error) {
+	return c.DescribeContext(context.Background())
+}
+
+// DescribeContext is like Describe but also bounded by ctx: if ctx is
+// cancelled or its deadline elapses before the guest returns, the call is
+// interrupted via wasmtime epoch deadlines and an error wrapping
+// ErrCassetteTimeout is returned.
+func (c *Cassette) DescribeContext(ctx context.Context) (string, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	// First check if there's a describe function
 	descFunc, hasDescribe := c.exports["describe"]
 	if hasDescribe {
-		result, err := descFunc.Call(c.store)
+		result, err := c.callWithDeadline(ctx, descFunc)
 		if err != nil {
 			return "", err
 		}
@@ -215,7 +441,7 @@ func (c *Cassette) Describe() (string, error) {
 			deallocFunc.Call(c.store, ptr, int32(len(desc)))
 		}
 
-		return desc, nil
+		return c.mergeAliasMetadata(desc), nil
 	}
 
 	// Otherwise, synthesize from Info()
@@ -224,7 +450,7 @@ func (c *Cassette) Describe() (string, error) {
 		return "Cassette with no description", nil
 	}
 
-	result, err := infoFunc.Call(c.store)
+	result, err := c.callWithDeadline(ctx, infoFunc)
 	if err != nil {
 		return "", err
 	}
@@ -244,6 +470,8 @@ func (c *Cassette) Describe() (string, error) {
 		deallocFunc.Call(c.store, ptr, int32(len(infoStr)))
 	}
 
+	infoStr = c.mergeAliasMetadata(infoStr)
+
 	// Parse info JSON to create description
 	var info map[string]interface{}
 	if err := json.Unmarshal([]byte(infoStr), &info); err != nil {
@@ -277,84 +505,317 @@ func (c *Cassette) Describe() (string, error) {
 	return "Cassette with no description", nil
 }
 
-// Send processes any NIP-01 message
-// For REQ messages, returns SendResult with Multiple set. For other messages, returns SendResult with Single set.
-func (c *Cassette) Send(message string) (*SendResult, error) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// parseMessageHead looks at the first element(s) of a NIP-01 client message
+// and reports whether it's a REQ (plus its subscription ID) or a CLOSE.
+func parseMessageHead(message string) (isReq bool, isClose bool, subscriptionID string) {
+	var msgData []interface{}
+	if err := json.Unmarshal([]byte(message), &msgData); err != nil || len(msgData) < 2 {
+		return false, false, ""
+	}
 
-	// Parse message to determine type
-	var isReqMessage bool
-	var subscriptionID string
+	msgType, ok := msgData[0].(string)
+	if !ok {
+		return false, false, ""
+	}
 
-	var msgData []interface{}
-	if err := json.Unmarshal([]byte(message), &msgData); err == nil {
-		if len(msgData) >= 2 {
-			msgType, ok := msgData[0].(string)
-			if ok {
-				switch msgType {
-				case "REQ":
-					// New REQ, reset event tracker
-					c.eventTracker.Reset()
-					if c.debug {
-						fmt.Println("[Cassette] New REQ, resetting event tracker")
-					}
-					isReqMessage = true
-					if subID, ok := msgData[1].(string); ok {
-						subscriptionID = subID
-					}
-				case "CLOSE":
-					// CLOSE message, reset event tracker for that subscription
-					c.eventTracker.Reset()
-					if c.debug {
-						fmt.Println("[Cassette] CLOSE message, resetting event tracker")
-					}
-				}
-			}
+	switch msgType {
+	case "REQ":
+		if subID, ok := msgData[1].(string); ok {
+			subscriptionID = subID
 		}
+		return true, false, subscriptionID
+	case "CLOSE":
+		if subID, ok := msgData[1].(string); ok {
+			subscriptionID = subID
+		}
+		return false, true, subscriptionID
+	default:
+		return false, false, ""
+	}
+}
+
+// isTerminalReqFrame reports whether a relay frame ends a REQ's subscription
+// (EOSE or CLOSED).
+func isTerminalReqFrame(frame string) bool {
+	var parsed []interface{}
+	if err := json.Unmarshal([]byte(frame), &parsed); err != nil || len(parsed) < 1 {
+		return false
 	}
+	msgType, ok := parsed[0].(string)
+	return ok && (msgType == "EOSE" || msgType == "CLOSED")
+}
 
-	// If it's a REQ message, collect all events until EOSE
-	if isReqMessage {
-		results, err := c.collectAllEventsForReq(message, subscriptionID)
-		if err != nil {
-			return nil, err
+// callOutcome carries a WASM call's result back from the goroutine running
+// it to callWithDeadline's select.
+type callOutcome struct {
+	result interface{}
+	err    error
+}
+
+// isEpochTrap reports whether err looks like a wasmtime epoch-interruption
+// trap, as opposed to a guest-raised error.
+func isEpochTrap(err error) bool {
+	var trap *wasmtime.Trap
+	if !errors.As(err, &trap) {
+		return false
+	}
+	code := trap.Code()
+	return code != nil && *code == wasmtime.Interrupt
+}
+
+// effectiveDeadline reports the earlier of ctx's own deadline and the
+// cassette's configured CallTimeout, if either applies.
+func (c *Cassette) effectiveDeadline(ctx context.Context) (time.Time, bool) {
+	ctxDeadline, hasCtxDeadline := ctx.Deadline()
+	if c.callTimeout <= 0 {
+		return ctxDeadline, hasCtxDeadline
+	}
+
+	timeoutDeadline := time.Now().Add(c.callTimeout)
+	if !hasCtxDeadline || timeoutDeadline.Before(ctxDeadline) {
+		return timeoutDeadline, true
+	}
+	return ctxDeadline, true
+}
+
+// epochTicksUntil converts a deadline into a tick count suitable for
+// Store.SetEpochDeadline, given the background ticker's interval.
+func epochTicksUntil(deadline time.Time) uint64 {
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return 1
+	}
+	return uint64(remaining/defaultEpochTickInterval) + 1
+}
+
+// callWithDeadline invokes fn.Call(c.store, args...) bounded by ctx and the
+// cassette's CallTimeout, whichever elapses first. If there is no deadline
+// and ctx can't be cancelled, fn is called directly with no extra
+// bookkeeping. Otherwise the store's epoch deadline is armed (effectively
+// infinite when only cancellation applies), and if the deadline or ctx fires before the guest
+// returns, the epoch deadline is forced to zero so wasmtime traps the
+// in-flight call; the trap is translated into an error wrapping
+// ErrCassetteTimeout.
+func (c *Cassette) callWithDeadline(ctx context.Context, fn *wasmtime.Func, args ...interface{}) (interface{}, error) {
+	deadline, hasDeadline := c.effectiveDeadline(ctx)
+	if !hasDeadline && ctx.Done() == nil {
+		// No deadline to enforce, but the store's epoch deadline still
+		// defaults to zero - arm an effectively-infinite one so this call
+		// doesn't trap on its first epoch check.
+		c.store.SetEpochDeadline(noEpochDeadlineTicks)
+		return fn.Call(c.store, args...)
+	}
+
+	var timeout <-chan time.Time
+	if hasDeadline {
+		c.store.SetEpochDeadline(epochTicksUntil(deadline))
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		timeout = timer.C
+	} else {
+		// Cancellation only - only the ctx.Done() branch below may trap.
+		c.store.SetEpochDeadline(noEpochDeadlineTicks)
+	}
+
+	done := make(chan callOutcome, 1)
+	go func() {
+		result, err := fn.Call(c.store, args...)
+		done <- callOutcome{result, err}
+	}()
+
+	select {
+	case out := <-done:
+		return out.result, out.err
+	case <-ctx.Done():
+		out := c.forceTrapAndAwait(done)
+		if isEpochTrap(out.err) {
+			return nil, fmt.Errorf("%w: %v", ErrCassetteTimeout, ctx.Err())
 		}
-		return &SendResult{IsSingle: false, Multiple: results}, nil
+		return out.result, out.err
+	case <-timeout:
+		out := c.forceTrapAndAwait(done)
+		if isEpochTrap(out.err) {
+			return nil, fmt.Errorf("%w: call exceeded CallTimeout", ErrCassetteTimeout)
+		}
+		return out.result, out.err
 	}
+}
+
+// forceTrapAndAwait forces wasmtime to trap the in-flight call by zeroing
+// the store's epoch deadline, then waits for the goroutine in
+// callWithDeadline to observe the trap and report outcome on done. The
+// deadline is re-armed to noEpochDeadlineTicks afterward - it only needs to
+// reach the in-flight call, and leaving it at zero would trap every later
+// call on this store too (e.g. sendSingle's deferred dealloc_string).
+func (c *Cassette) forceTrapAndAwait(done <-chan callOutcome) callOutcome {
+	c.store.SetEpochDeadline(0)
+	out := <-done
+	c.store.SetEpochDeadline(noEpochDeadlineTicks)
+	return out
+}
+
+// SendStream processes a NIP-01 message and emits each resulting frame on
+// the returned channel as soon as the WASM guest produces it, rather than
+// buffering every event into a slice before returning. For a REQ message it
+// keeps calling the guest's send function until an EOSE/CLOSED frame is
+// seen; for any other message it emits at most one frame. The out channel
+// is closed when the subscription terminates, the guest returns an empty
+// response, ctx is cancelled, or a sendSingle call fails - ctx.Done() is
+// checked between WASM invocations so a long-running REQ can be cancelled
+// without waiting for the guest to finish. A sendSingle failure stops the
+// stream immediately (no synthesized EOSE) and is delivered on errCh, which
+// is always closed once out is; read it after draining out to see whether
+// the stream ended cleanly. Send is implemented on top of this.
+func (c *Cassette) SendStream(ctx context.Context, message string) (<-chan string, <-chan error, error) {
+	isReq, isClose, subscriptionID := parseMessageHead(message)
+	out := make(chan string, 16)
+	errCh := make(chan error, 1)
+
+	go func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		defer close(errCh)
+		defer close(out)
+
+		if isReq {
+			c.subscriptions.Open(subscriptionID)
+			if c.debug {
+				fmt.Printf("[Cassette] New REQ, opening dedup tracker for subscription %s\n", subscriptionID)
+			}
+		} else if isClose {
+			c.subscriptions.Close(subscriptionID)
+			if c.debug {
+				fmt.Printf("[Cassette] CLOSE message, freeing dedup tracker for subscription %s\n", subscriptionID)
+			}
+		}
+
+		if !isReq {
+			response, err := c.sendSingle(ctx, message)
+			if err != nil {
+				errCh <- err
+				return
+			}
+			if response == "" {
+				return
+			}
+			select {
+			case out <- response:
+			case <-ctx.Done():
+			}
+			return
+		}
 
-	// For non-REQ messages, use single call
-	result, err := c.sendSingle(message)
+		sawTerminal := false
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			response, err := c.sendSingle(ctx, message)
+			if err != nil {
+				errCh <- err
+				return
+			}
+			if response == "" {
+				if c.debug {
+					fmt.Println("[Cassette] Received empty response, stopping")
+				}
+				break
+			}
+
+			select {
+			case out <- response:
+			case <-ctx.Done():
+				return
+			}
+
+			if isTerminalReqFrame(response) {
+				sawTerminal = true
+				break
+			}
+		}
+
+		if !sawTerminal {
+			eose, _ := json.Marshal([]interface{}{"EOSE", subscriptionID})
+			select {
+			case out <- string(eose):
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return out, errCh, nil
+}
+
+// Send processes any NIP-01 message
+// For REQ messages, returns SendResult with Multiple set. For other messages, returns SendResult with Single set.
+func (c *Cassette) Send(message string) (*SendResult, error) {
+	return c.SendContext(context.Background(), message)
+}
+
+// SendContext is like Send but bounded by ctx: if ctx is cancelled or its
+// deadline elapses before the guest finishes, the in-flight WASM call is
+// interrupted via wasmtime epoch deadlines and the stream ends with an error
+// wrapping ErrCassetteTimeout.
+func (c *Cassette) SendContext(ctx context.Context, message string) (*SendResult, error) {
+	isReq, _, _ := parseMessageHead(message)
+
+	ch, errCh, err := c.SendStream(ctx, message)
 	if err != nil {
 		return nil, err
 	}
-	return &SendResult{IsSingle: true, Single: result}, nil
+
+	if !isReq {
+		single := ""
+		for msg := range ch {
+			single = msg
+		}
+		if streamErr := <-errCh; streamErr != nil {
+			return nil, streamErr
+		}
+		return &SendResult{IsSingle: true, Single: single}, nil
+	}
+
+	var results []string
+	for msg := range ch {
+		results = append(results, msg)
+	}
+	if streamErr := <-errCh; streamErr != nil {
+		return nil, streamErr
+	}
+	return &SendResult{IsSingle: false, Multiple: results}, nil
 }
 
-// sendSingle performs a single send call
-func (c *Cassette) sendSingle(message string) (string, error) {
+// sendSingle performs a single send call, bounded by ctx and the cassette's
+// CallTimeout via callWithDeadline.
+func (c *Cassette) sendSingle(ctx context.Context, message string) (string, error) {
 	// Write message to memory
 	msgPtr, err := c.memory.WriteString(message)
 	if err != nil {
 		return "", err
 	}
 
+	// Deallocate the message pointer no matter how the call below ends, so
+	// a cancelled/timed-out Send doesn't leak guest memory.
+	defer func() {
+		if deallocFunc, ok := c.exports["dealloc_string"]; ok {
+			deallocFunc.Call(c.store, msgPtr, int32(len(message)))
+		}
+	}()
+
 	// Call send function
 	sendFunc, ok := c.exports["send"]
 	if !ok {
 		return "", fmt.Errorf("send function not found")
 	}
 
-	result, err := sendFunc.Call(c.store, msgPtr, int32(len(message)))
+	result, err := c.callWithDeadline(ctx, sendFunc, msgPtr, int32(len(message)))
 	if err != nil {
 		return "", err
 	}
 
-	// Deallocate message
-	if deallocFunc, ok := c.exports["dealloc_string"]; ok {
-		deallocFunc.Call(c.store, msgPtr, int32(len(message)))
-	}
-
 	resultPtr := result.(int32)
 	if resultPtr == 0 {
 		return `["NOTICE", "send() returned null pointer"]`, nil
@@ -415,19 +876,22 @@ func (c *Cassette) processResults(resultStr string) string {
 				continue
 			}
 
-			// Filter duplicate events
+			subID, _ := parsed[1].(string)
+
+			// Filter duplicate events, scoped to this message's own subscription
 			if msgType == "EVENT" && len(parsed) >= 3 {
 				if eventMap, ok := parsed[2].(map[string]interface{}); ok {
 					if eventID, ok := eventMap["id"].(string); ok {
-						if !c.eventTracker.AddAndCheck(eventID) {
+						if !c.subscriptions.AddAndCheck(subID, eventID) {
 							if c.debug {
-								fmt.Printf("[Cassette] Filtering duplicate event: %s\n", eventID)
+								fmt.Printf("[Cassette] Filtering duplicate event %s on subscription %s\n", eventID, subID)
 							}
 							continue
 						}
 					}
 				}
 			}
+			c.subscriptions.CloseOnTerminalFrame(subID, msgType)
 
 			filteredMessages = append(filteredMessages, message)
 		}
@@ -440,106 +904,37 @@ func (c *Cassette) processResults(resultStr string) string {
 
 	// Single message - check for duplicate
 	var parsed []interface{}
-	if err := json.Unmarshal([]byte(resultStr), &parsed); err == nil {
-		if len(parsed) >= 3 && parsed[0] == "EVENT" {
+	if err := json.Unmarshal([]byte(resultStr), &parsed); err == nil && len(parsed) >= 2 {
+		msgType, _ := parsed[0].(string)
+		subID, _ := parsed[1].(string)
+
+		if msgType == "EVENT" && len(parsed) >= 3 {
 			if eventMap, ok := parsed[2].(map[string]interface{}); ok {
 				if eventID, ok := eventMap["id"].(string); ok {
-					if !c.eventTracker.AddAndCheck(eventID) {
+					if !c.subscriptions.AddAndCheck(subID, eventID) {
 						if c.debug {
-							fmt.Printf("[Cassette] Filtering duplicate event: %s\n", eventID)
+							fmt.Printf("[Cassette] Filtering duplicate event %s on subscription %s\n", eventID, subID)
 						}
 						return ""
 					}
 				}
 			}
 		}
+		c.subscriptions.CloseOnTerminalFrame(subID, msgType)
 	}
 
 	return resultStr
 }
 
-// collectAllEventsForReq collects all events for a REQ message until EOSE
-func (c *Cassette) collectAllEventsForReq(message string, subscriptionID string) ([]string, error) {
-	if c.debug {
-		fmt.Printf("[Cassette] Collecting all events for REQ subscription: %s\n", subscriptionID)
-	}
-
-	var results []string
-
-	// Keep calling until we get EOSE or terminating condition
-	for {
-		response, err := c.sendSingle(message)
-		if err != nil {
-			return nil, err
-		}
-
-		// Empty response means no more events
-		if response == "" {
-			if c.debug {
-				fmt.Println("[Cassette] Received empty response, stopping")
-			}
-			break
-		}
-
-		// Try to parse the response
-		var parsed []interface{}
-		if err := json.Unmarshal([]byte(response), &parsed); err != nil {
-			if c.debug {
-				fmt.Printf("[Cassette] Failed to parse response: %v, stopping\n", err)
-			}
-			break
-		}
-
-		if len(parsed) >= 1 {
-			msgType, ok := parsed[0].(string)
-			if ok {
-				switch msgType {
-				case "EOSE":
-					if c.debug {
-						fmt.Printf("[Cassette] Received EOSE for subscription %s\n", subscriptionID)
-					}
-					results = append(results, response)
-					goto done
-				case "CLOSED":
-					if c.debug {
-						fmt.Printf("[Cassette] Received CLOSED for subscription %s\n", subscriptionID)
-					}
-					results = append(results, response)
-					goto done
-				}
-			}
-		}
-
-		// Add the response to results
-		results = append(results, response)
-	}
-
-done:
-	// Check if we have an EOSE message
-	hasEOSE := false
-	for _, r := range results {
-		var parsed []interface{}
-		if err := json.Unmarshal([]byte(r), &parsed); err == nil {
-			if len(parsed) >= 1 && parsed[0] == "EOSE" {
-				hasEOSE = true
-				break
-			}
-		}
-	}
-
-	// If no EOSE, add one
-	if !hasEOSE {
-		eose, _ := json.Marshal([]interface{}{"EOSE", subscriptionID})
-		results = append(results, string(eose))
-	}
-
-	return results, nil
+// Info returns NIP-11 relay information, bounded only by the cassette's
+// configured CallTimeout (if any).
+func (c *Cassette) Info() (string, error) {
+	return c.InfoContext(context.Background())
 }
 
-
-
-// Info returns NIP-11 relay information
-func (c *Cassette) Info() (string, error) {
+// InfoContext is like Info but also bounded by ctx, per the same rules as
+// DescribeContext.
+func (c *Cassette) InfoContext(ctx context.Context) (string, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -550,7 +945,7 @@ func (c *Cassette) Info() (string, error) {
 	}
 
 	// Call info function
-	result, err := infoFunc.Call(c.store)
+	result, err := c.callWithDeadline(ctx, infoFunc)
 	if err != nil {
 		return "", err
 	}
@@ -571,5 +966,175 @@ func (c *Cassette) Info() (string, error) {
 		deallocFunc.Call(c.store, ptr, int32(len(infoStr)))
 	}
 
-	return infoStr, nil
+	return c.mergeAliasMetadata(infoStr), nil
+}
+
+// poolConfig holds CassettePool's construction-time settings.
+type poolConfig struct {
+	sharedDedup bool
+}
+
+// PoolOption configures a CassettePool.
+type PoolOption func(*poolConfig)
+
+// WithSharedDedup controls whether a CassettePool's workers share one
+// SubscriptionRegistry (the default) or each get an independent one. A
+// shared registry keeps dedup correct if the same subscription ID can be
+// routed to different workers across calls; independent registries avoid
+// lock contention when callers instead pin a subscription to one worker.
+func WithSharedDedup(shared bool) PoolOption {
+	return func(cfg *poolConfig) { cfg.sharedDedup = shared }
+}
+
+// CassettePool loads a cassette's WASM module once and runs `size`
+// independent Store+Instance workers behind a checkout/checkin API, so
+// Send/Info/Describe calls from many concurrent callers don't queue behind
+// a single WASM instance's mutex the way a lone Cassette would force them
+// to.
+type CassettePool struct {
+	mu        sync.Mutex
+	cond      *sync.Cond
+	idle      []*Cassette
+	all       []*Cassette
+	epochStop func()
+}
+
+// LoadCassettePool loads path's WASM module once on a shared Engine and
+// instantiates `size` workers against it.
+func LoadCassettePool(path string, size int, debug bool, opts ...PoolOption) (*CassettePool, error) {
+	return LoadCassettePoolWithOptions(path, size, debug, CassetteOptions{}, opts...)
+}
+
+// LoadCassettePoolWithOptions is like LoadCassettePool but accepts
+// CassetteOptions applied to every worker (currently just CallTimeout).
+func LoadCassettePoolWithOptions(path string, size int, debug bool, copts CassetteOptions, opts ...PoolOption) (*CassettePool, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("pool size must be at least 1")
+	}
+
+	cfg := poolConfig{sharedDedup: true}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	engine := newEngineWithEpochInterruption()
+	module, err := wasmtime.NewModuleFromFile(engine, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load module: %w", err)
+	}
+
+	var shared *SubscriptionRegistry
+	if cfg.sharedDedup {
+		shared = NewSubscriptionRegistry(defaultMaxEventsPerSubscription, true)
+	}
+
+	pool := &CassettePool{}
+	pool.cond = sync.NewCond(&pool.mu)
+
+	for i := 0; i < size; i++ {
+		worker, err := newCassetteInstance(engine, module, debug, shared, copts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to instantiate pool worker %d: %w", i, err)
+		}
+		pool.all = append(pool.all, worker)
+		pool.idle = append(pool.idle, worker)
+	}
+
+	pool.epochStop = startEpochTicker(engine)
+	return pool, nil
+}
+
+// Close stops the pool's shared background epoch ticker. It's safe to call
+// more than once.
+func (p *CassettePool) Close() {
+	if p.epochStop != nil {
+		p.epochStop()
+		p.epochStop = nil
+	}
+}
+
+// checkout blocks until an idle worker is available.
+func (p *CassettePool) checkout() *Cassette {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for len(p.idle) == 0 {
+		p.cond.Wait()
+	}
+	worker := p.idle[len(p.idle)-1]
+	p.idle = p.idle[:len(p.idle)-1]
+	return worker
+}
+
+// checkin returns a worker to the idle pool.
+func (p *CassettePool) checkin(worker *Cassette) {
+	p.mu.Lock()
+	p.idle = append(p.idle, worker)
+	p.mu.Unlock()
+	p.cond.Signal()
+}
+
+// Send checks out an idle worker, forwards message to it, and returns the
+// worker to the pool once it replies.
+func (p *CassettePool) Send(message string) (*SendResult, error) {
+	worker := p.checkout()
+	defer p.checkin(worker)
+	return worker.Send(message)
+}
+
+// SendContext is like Send but bounded by ctx, per the same rules as
+// Cassette.SendContext.
+func (p *CassettePool) SendContext(ctx context.Context, message string) (*SendResult, error) {
+	worker := p.checkout()
+	defer p.checkin(worker)
+	return worker.SendContext(ctx, message)
+}
+
+// SendStream checks out an idle worker and streams through it; the worker
+// isn't returned to the pool until the underlying stream is fully drained
+// or ctx is cancelled. errCh carries any sendSingle failure from the
+// worker's stream, per the same contract as Cassette.SendStream.
+func (p *CassettePool) SendStream(ctx context.Context, message string) (<-chan string, <-chan error, error) {
+	worker := p.checkout()
+	ch, workerErrCh, err := worker.SendStream(ctx, message)
+	if err != nil {
+		p.checkin(worker)
+		return nil, nil, err
+	}
+
+	out := make(chan string, 16)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(out)
+		defer close(errCh)
+		defer p.checkin(worker)
+		for msg := range ch {
+			select {
+			case out <- msg:
+			case <-ctx.Done():
+			}
+		}
+		if err := <-workerErrCh; err != nil {
+			errCh <- err
+		}
+	}()
+	return out, errCh, nil
+}
+
+// Info checks out an idle worker to serve an Info() call.
+func (p *CassettePool) Info() (string, error) {
+	worker := p.checkout()
+	defer p.checkin(worker)
+	return worker.Info()
+}
+
+// Describe checks out an idle worker to serve a Describe() call.
+func (p *CassettePool) Describe() (string, error) {
+	worker := p.checkout()
+	defer p.checkin(worker)
+	return worker.Describe()
+}
+
+// Size returns the number of workers in the pool.
+func (p *CassettePool) Size() int {
+	return len(p.all)
 }
\ No newline at end of file
@@ -0,0 +1,265 @@
+package cassette
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// ErrAliasNotFound is returned by a CassetteRegistry's Resolve (or
+// Metadata) when no cassette is registered under the given alias.
+var ErrAliasNotFound = errors.New("cassette: alias not found")
+
+// CassetteRegistry resolves friendly aliases to cassette file paths, so
+// operators can refer to cassettes by name instead of on-disk filename.
+type CassetteRegistry interface {
+	// Resolve looks up alias and returns the cassette path it points to.
+	Resolve(alias string) (path string, err error)
+	// Register associates alias with path and optional metadata (e.g.
+	// display name, tags, owner pubkey), overwriting any existing entry.
+	Register(alias, path string, meta map[string]string) error
+}
+
+// MetadataRegistry is implemented by registries that can also return the
+// metadata passed to Register, so LoadCassetteByAlias can merge it into the
+// loaded cassette's NIP-11 document. It's a separate interface from
+// CassetteRegistry because some backends (e.g. a bare path-only KV) may not
+// carry metadata at all.
+type MetadataRegistry interface {
+	Metadata(alias string) (map[string]string, error)
+}
+
+// registryEntry is the persisted shape of one alias in FileRegistry and
+// KVRegistry.
+type registryEntry struct {
+	Path string            `json:"path"`
+	Meta map[string]string `json:"meta,omitempty"`
+}
+
+// MemoryRegistry is an in-memory CassetteRegistry. It's the simplest
+// implementation, useful for tests or single-process deployments that don't
+// need aliases to survive a restart.
+type MemoryRegistry struct {
+	mu      sync.RWMutex
+	entries map[string]registryEntry
+}
+
+// NewMemoryRegistry creates an empty MemoryRegistry.
+func NewMemoryRegistry() *MemoryRegistry {
+	return &MemoryRegistry{entries: make(map[string]registryEntry)}
+}
+
+// Resolve implements CassetteRegistry.
+func (r *MemoryRegistry) Resolve(alias string) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.entries[alias]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrAliasNotFound, alias)
+	}
+	return entry.Path, nil
+}
+
+// Register implements CassetteRegistry.
+func (r *MemoryRegistry) Register(alias, path string, meta map[string]string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[alias] = registryEntry{Path: path, Meta: meta}
+	return nil
+}
+
+// Metadata implements MetadataRegistry.
+func (r *MemoryRegistry) Metadata(alias string) (map[string]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.entries[alias]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrAliasNotFound, alias)
+	}
+	return entry.Meta, nil
+}
+
+// FileRegistry is a CassetteRegistry backed by a JSON file on disk,
+// persisting entries added via Register. It reloads the file on every call
+// so edits made by another process (or another FileRegistry instance) are
+// picked up.
+type FileRegistry struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileRegistry creates a FileRegistry backed by path. The file need not
+// exist yet - it's created on first Register.
+func NewFileRegistry(path string) *FileRegistry {
+	return &FileRegistry{path: path}
+}
+
+func (r *FileRegistry) load() (map[string]registryEntry, error) {
+	data, err := os.ReadFile(r.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return make(map[string]registryEntry), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return make(map[string]registryEntry), nil
+	}
+
+	entries := make(map[string]registryEntry)
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse registry file %s: %w", r.path, err)
+	}
+	return entries, nil
+}
+
+func (r *FileRegistry) save(entries map[string]registryEntry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, data, 0o644)
+}
+
+// Resolve implements CassetteRegistry.
+func (r *FileRegistry) Resolve(alias string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries, err := r.load()
+	if err != nil {
+		return "", err
+	}
+	entry, ok := entries[alias]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrAliasNotFound, alias)
+	}
+	return entry.Path, nil
+}
+
+// Register implements CassetteRegistry.
+func (r *FileRegistry) Register(alias, path string, meta map[string]string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries, err := r.load()
+	if err != nil {
+		return err
+	}
+	entries[alias] = registryEntry{Path: path, Meta: meta}
+	return r.save(entries)
+}
+
+// Metadata implements MetadataRegistry.
+func (r *FileRegistry) Metadata(alias string) (map[string]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := entries[alias]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrAliasNotFound, alias)
+	}
+	return entry.Meta, nil
+}
+
+// ErrKVKeyNotFound is the sentinel a KVStore's Get should return (or wrap)
+// when the requested key doesn't exist, so KVRegistry can translate it into
+// ErrAliasNotFound.
+var ErrKVKeyNotFound = errors.New("cassette: kv key not found")
+
+// KVStore is the minimal key/value interface KVRegistry needs. BoltDB,
+// Redis, or any other store can satisfy it with a thin adapter.
+type KVStore interface {
+	Get(key string) ([]byte, error)
+	Set(key string, value []byte) error
+}
+
+// kvAliasKeyPrefix namespaces alias entries within a KVStore that may be
+// shared with other data.
+const kvAliasKeyPrefix = "cassette:alias:"
+
+// KVRegistry is a CassetteRegistry backed by any KVStore (BoltDB, Redis,
+// etc.), storing each alias's path and metadata as a JSON blob under a
+// namespaced key.
+type KVRegistry struct {
+	store KVStore
+}
+
+// NewKVRegistry creates a KVRegistry backed by store.
+func NewKVRegistry(store KVStore) *KVRegistry {
+	return &KVRegistry{store: store}
+}
+
+func (r *KVRegistry) get(alias string) (registryEntry, error) {
+	data, err := r.store.Get(kvAliasKeyPrefix + alias)
+	if errors.Is(err, ErrKVKeyNotFound) {
+		return registryEntry{}, fmt.Errorf("%w: %s", ErrAliasNotFound, alias)
+	}
+	if err != nil {
+		return registryEntry{}, err
+	}
+
+	var entry registryEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return registryEntry{}, fmt.Errorf("failed to parse registry entry for %s: %w", alias, err)
+	}
+	return entry, nil
+}
+
+// Resolve implements CassetteRegistry.
+func (r *KVRegistry) Resolve(alias string) (string, error) {
+	entry, err := r.get(alias)
+	if err != nil {
+		return "", err
+	}
+	return entry.Path, nil
+}
+
+// Register implements CassetteRegistry.
+func (r *KVRegistry) Register(alias, path string, meta map[string]string) error {
+	data, err := json.Marshal(registryEntry{Path: path, Meta: meta})
+	if err != nil {
+		return err
+	}
+	return r.store.Set(kvAliasKeyPrefix+alias, data)
+}
+
+// Metadata implements MetadataRegistry.
+func (r *KVRegistry) Metadata(alias string) (map[string]string, error) {
+	entry, err := r.get(alias)
+	if err != nil {
+		return nil, err
+	}
+	return entry.Meta, nil
+}
+
+// LoadCassetteByAlias resolves alias via registry and loads the cassette it
+// points to. If registry also implements MetadataRegistry, the alias's
+// metadata is merged into the cassette's Info/Describe output (display
+// name, tags, owner pubkey, etc. under an "alias" key).
+func LoadCassetteByAlias(registry CassetteRegistry, alias string, debug bool) (*Cassette, error) {
+	path, err := registry.Resolve(alias)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve alias %q: %w", alias, err)
+	}
+
+	c, err := LoadCassette(path, debug)
+	if err != nil {
+		return nil, err
+	}
+	c.alias = alias
+
+	if metaRegistry, ok := registry.(MetadataRegistry); ok {
+		if meta, err := metaRegistry.Metadata(alias); err == nil {
+			c.aliasMeta = meta
+		}
+	}
+
+	return c, nil
+}
@@ -1,45 +1,114 @@
 package cassette
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/crc32"
+	"os"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/bytecodealliance/wasmtime-go/v23"
 )
 
-// EventTracker manages event deduplication
+// ErrCassetteTimeout is returned when a WASM call (Send/Info/Describe) is
+// interrupted because its context was cancelled or its deadline elapsed
+// before the guest returned.
+var ErrCassetteTimeout = errors.New("cassette: call timed out")
+
+// epochTickInterval is how often a Cassette's background ticker bumps its
+// engine's epoch counter. wasmtime compares this counter against the
+// Store's epoch deadline to decide when to trap a runaway guest call.
+const epochTickInterval = 10 * time.Millisecond
+
+// noEpochDeadlineTicks arms a Store's epoch deadline far enough in the
+// future that the background ticker alone will never trip it; used when ctx
+// is cancellable but carries no deadline, so only an explicit ctx.Done()
+// forces the trap.
+const noEpochDeadlineTicks = 1 << 62
+
+// defaultMaxEventsPerSubscription bounds how many event IDs a single
+// subscription's dedup state retains before evicting the oldest ones, so a
+// long-running REQ doesn't grow its tracker unboundedly.
+const defaultMaxEventsPerSubscription = 100000
+
+// subTracker holds the bounded dedup state for a single subscription ID,
+// with FIFO eviction once maxEvents is reached.
+type subTracker struct {
+	seen  map[string]bool
+	order []string
+}
+
+func newSubTracker() *subTracker {
+	return &subTracker{seen: make(map[string]bool)}
+}
+
+func (t *subTracker) addAndCheck(eventID string, maxEvents int) bool {
+	if t.seen[eventID] {
+		return false
+	}
+
+	if maxEvents > 0 && len(t.order) >= maxEvents {
+		oldest := t.order[0]
+		t.order = t.order[1:]
+		delete(t.seen, oldest)
+	}
+
+	t.seen[eventID] = true
+	t.order = append(t.order, eventID)
+	return true
+}
+
+// EventTracker manages event deduplication per subscription ID, so that
+// opening or closing one subscription no longer wipes the dedup state of
+// every other subscription multiplexed over the same cassette.
 type EventTracker struct {
-	mu         sync.Mutex
-	eventIDs   map[string]bool
-	subscripID string
+	mu        sync.Mutex
+	subs      map[string]*subTracker
+	maxEvents int
 }
 
-// NewEventTracker creates a new event tracker
-func NewEventTracker() *EventTracker {
+// NewEventTracker creates a new event tracker. maxEvents bounds each
+// subscription's retained event IDs (0 means unbounded).
+func NewEventTracker(maxEvents int) *EventTracker {
 	return &EventTracker{
-		eventIDs: make(map[string]bool),
+		subs:      make(map[string]*subTracker),
+		maxEvents: maxEvents,
 	}
 }
 
-// Reset clears the event tracker
-func (et *EventTracker) Reset() {
+// ResetSub clears dedup state for a single subscription ID, called on REQ
+// (to start that subscription fresh) and CLOSE (to free it).
+func (et *EventTracker) ResetSub(subID string) {
 	et.mu.Lock()
 	defer et.mu.Unlock()
-	et.eventIDs = make(map[string]bool)
+	delete(et.subs, subID)
 }
 
-// AddAndCheck adds an event ID and returns true if it's new
-func (et *EventTracker) AddAndCheck(eventID string) bool {
+// ResetAll clears dedup state for every subscription.
+func (et *EventTracker) ResetAll() {
 	et.mu.Lock()
 	defer et.mu.Unlock()
-	
-	if et.eventIDs[eventID] {
-		return false
+	et.subs = make(map[string]*subTracker)
+}
+
+// AddAndCheck adds an event ID to subID's tracker (allocating one on first
+// use) and returns true if it's new.
+func (et *EventTracker) AddAndCheck(subID, eventID string) bool {
+	et.mu.Lock()
+	defer et.mu.Unlock()
+
+	tracker, ok := et.subs[subID]
+	if !ok {
+		tracker = newSubTracker()
+		et.subs[subID] = tracker
 	}
-	et.eventIDs[eventID] = true
-	return true
+	return tracker.addAndCheck(eventID, et.maxEvents)
 }
 
 // MemoryManager handles WASM memory operations
@@ -68,7 +137,88 @@ func NewMemoryManager(store *wasmtime.Store, instance *wasmtime.Instance) (*Memo
 	}, nil
 }
 
-// WriteString writes a string to WASM memory
+// FrameType identifies the payload kind carried by a versioned MSGB frame.
+type FrameType uint8
+
+const (
+	FrameRaw        FrameType = 0
+	FrameJSON       FrameType = 1
+	FrameNostrEvent FrameType = 2
+	FrameError      FrameType = 3
+)
+
+// Frame is a parsed versioned MSGB frame (see WriteFrame/ReadFrame).
+type Frame struct {
+	Version uint8
+	Type    FrameType
+	Flags   uint16
+	Payload []byte
+}
+
+const (
+	// msgbLegacyMagic identifies the pre-existing MSGB layout: magic(4) +
+	// length(4 LE), no version/type/flags/crc32. Still recognized so
+	// cassettes built against the old framing keep working.
+	msgbLegacyMagic = "MSGB"
+
+	// msgbMagic identifies the versioned layout: magic(4) + version(1) +
+	// type(1) + flags(2) + length(4 LE) + crc32(4 LE). It's deliberately a
+	// different 4 bytes from msgbLegacyMagic - reusing "MSGB" and
+	// discriminating on a version byte at the same offset as the legacy
+	// length field's low byte is ambiguous (a legacy length not a multiple
+	// of 256 would be misread as a versioned frame).
+	msgbMagic = "MSG2"
+
+	msgbLegacyHeaderSize = 8
+
+	msgbHeaderSize = 16
+
+	// msgbFrameVersion is the version WriteFrame/WriteString emit.
+	msgbFrameVersion = 1
+)
+
+// errNotMSGBFrame is returned by ReadFrame when ptr doesn't begin with the
+// MSGB magic at all, signaling ReadString to fall back to plain
+// null-terminated parsing rather than treating it as corruption.
+var errNotMSGBFrame = errors.New("cassette: not an MSGB frame")
+
+// WriteFrame writes payload into WASM memory as a versioned MSGB frame
+// (magic, version, msgType, flags, length and a CRC32 of payload), so the
+// guest can validate what the host handed it instead of trusting a raw byte
+// copy. It returns the pointer to the frame's start.
+func (mm *MemoryManager) WriteFrame(payload []byte, msgType uint8) (int32, error) {
+	frame := make([]byte, msgbHeaderSize+len(payload))
+	copy(frame[0:4], msgbMagic)
+	frame[4] = msgbFrameVersion
+	frame[5] = msgType
+	// flags (bytes 6-7) are reserved and left zero for now.
+	binary.LittleEndian.PutUint32(frame[8:12], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(frame[12:16], crc32.ChecksumIEEE(payload))
+	copy(frame[msgbHeaderSize:], payload)
+
+	ptr, err := mm.allocFunc.Call(mm.store, int32(len(frame)))
+	if err != nil {
+		return 0, err
+	}
+
+	ptrInt := ptr.(int32)
+	if ptrInt == 0 {
+		return 0, fmt.Errorf("allocation failed")
+	}
+
+	memData := mm.memory.UnsafeData(mm.store)
+	copy(memData[ptrInt:], frame)
+
+	return ptrInt, nil
+}
+
+// WriteString writes a string to WASM memory as a plain, unframed buffer.
+// It's used exclusively for arguments passed into guest exports (send,
+// etc.), which read their input as raw bytes at ptr+len and have no MSGB
+// parsing of their own - wrapping it in a WriteFrame header would hand the
+// guest a pointer into the frame header instead of the payload. WriteFrame
+// (and the versioned MSGB layout it produces) is reserved for data the host
+// itself reads back via ReadFrame/ReadString.
 func (mm *MemoryManager) WriteString(s string) (int32, error) {
 	data := []byte(s)
 	ptr, err := mm.allocFunc.Call(mm.store, int32(len(data)))
@@ -83,40 +233,88 @@ func (mm *MemoryManager) WriteString(s string) (int32, error) {
 
 	memData := mm.memory.UnsafeData(mm.store)
 	copy(memData[ptrInt:], data)
-	
+
 	return ptrInt, nil
 }
 
-// ReadString reads a string from WASM memory (handles MSGB format)
-func (mm *MemoryManager) ReadString(ptr int32) (string, error) {
+// ReadFrame reads and validates the MSGB frame at ptr. A "MSG2" frame is
+// the versioned layout, with its CRC32 verified so memory corruption
+// surfaces as an error instead of silently returning a truncated payload. A
+// "MSGB" frame is the legacy magic(4)+length(4) layout, read as-is with no
+// CRC check. If ptr starts with neither magic, it returns errNotMSGBFrame.
+func (mm *MemoryManager) ReadFrame(ptr int32) (Frame, error) {
 	if ptr == 0 {
-		return "", fmt.Errorf("null pointer")
+		return Frame{}, fmt.Errorf("null pointer")
 	}
 
 	memData := mm.memory.UnsafeData(mm.store)
-	
-	// Check for MSGB signature
-	if ptr+8 <= int32(len(memData)) {
-		signature := string(memData[ptr:ptr+4])
-		if signature == "MSGB" {
-			// Read length from bytes 4-7 (little endian)
-			length := int32(memData[ptr+4]) |
-				int32(memData[ptr+5])<<8 |
-				int32(memData[ptr+6])<<16 |
-				int32(memData[ptr+7])<<24
-			
-			if ptr+8+length <= int32(len(memData)) {
-				return string(memData[ptr+8:ptr+8+length]), nil
-			}
+
+	if ptr+4 > int32(len(memData)) {
+		return Frame{}, errNotMSGBFrame
+	}
+	magic := string(memData[ptr : ptr+4])
+
+	if magic == msgbLegacyMagic {
+		if ptr+msgbLegacyHeaderSize > int32(len(memData)) {
+			return Frame{}, errNotMSGBFrame
+		}
+		length := int32(binary.LittleEndian.Uint32(memData[ptr+4 : ptr+8]))
+		if ptr+msgbLegacyHeaderSize+length > int32(len(memData)) {
+			return Frame{}, fmt.Errorf("truncated legacy MSGB frame")
 		}
+		return Frame{Version: 0, Type: FrameRaw, Payload: memData[ptr+msgbLegacyHeaderSize : ptr+msgbLegacyHeaderSize+length]}, nil
 	}
-	
+
+	if magic != msgbMagic {
+		return Frame{}, errNotMSGBFrame
+	}
+
+	if ptr+msgbHeaderSize > int32(len(memData)) {
+		return Frame{}, fmt.Errorf("truncated MSGB header")
+	}
+
+	version := memData[ptr+4]
+	frameType := memData[ptr+5]
+	flags := binary.LittleEndian.Uint16(memData[ptr+6 : ptr+8])
+	length := int32(binary.LittleEndian.Uint32(memData[ptr+8 : ptr+12]))
+	wantCRC := binary.LittleEndian.Uint32(memData[ptr+12 : ptr+16])
+
+	if ptr+msgbHeaderSize+length > int32(len(memData)) {
+		return Frame{}, fmt.Errorf("truncated MSGB frame payload")
+	}
+
+	payload := memData[ptr+msgbHeaderSize : ptr+msgbHeaderSize+length]
+	if gotCRC := crc32.ChecksumIEEE(payload); gotCRC != wantCRC {
+		return Frame{}, fmt.Errorf("MSGB frame CRC mismatch: got %08x, want %08x", gotCRC, wantCRC)
+	}
+
+	return Frame{Version: version, Type: FrameType(frameType), Flags: flags, Payload: payload}, nil
+}
+
+// ReadString reads a string from WASM memory. It prefers the versioned MSGB
+// framing (validating the CRC and surfacing corruption as an error) or the
+// legacy MSGB layout, and falls back to null-terminated parsing when ptr
+// carries no MSGB signature at all.
+func (mm *MemoryManager) ReadString(ptr int32) (string, error) {
+	if ptr == 0 {
+		return "", fmt.Errorf("null pointer")
+	}
+
+	frame, err := mm.ReadFrame(ptr)
+	if err == nil {
+		return string(frame.Payload), nil
+	}
+	if !errors.Is(err, errNotMSGBFrame) {
+		return "", err
+	}
+
 	// Fall back to null-terminated string
+	memData := mm.memory.UnsafeData(mm.store)
 	end := ptr
 	for end < int32(len(memData)) && memData[end] != 0 {
 		end++
 	}
-	
+
 	return string(memData[ptr:end]), nil
 }
 
@@ -126,21 +324,46 @@ type Cassette struct {
 	store        *wasmtime.Store
 	instance     *wasmtime.Instance
 	memory       *MemoryManager
-	eventTracker *EventTracker
+	dedupStore   DedupStore
+	cassetteHash string
 	exports      map[string]*wasmtime.Func
 	debug        bool
 	mu           sync.Mutex
+	epochStop    func()
 }
 
-// LoadCassette loads a cassette from a WASM file
-func LoadCassette(path string, debug bool) (*Cassette, error) {
-	engine := wasmtime.NewEngine()
-	module, err := wasmtime.NewModuleFromFile(engine, path)
+// LoadCassette loads a cassette from a WASM file. The returned Cassette's
+// engine runs epoch interruption (see SendContext/InfoContext/DescribeContext)
+// so a malformed or adversarial cassette can't hang a call indefinitely.
+// By default, event dedup is kept in memory only; pass WithDedupStore to
+// use a persistent backend instead.
+func LoadCassette(path string, debug bool, opts ...LoadOption) (*Cassette, error) {
+	cfg := loadConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	wasmBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module: %w", err)
+	}
+	cassetteHash := fmt.Sprintf("%x", sha256.Sum256(wasmBytes))
+
+	engineCfg := wasmtime.NewConfig()
+	engineCfg.SetEpochInterruption(true)
+	engine := wasmtime.NewEngineWithConfig(engineCfg)
+	module, err := wasmtime.NewModule(engine, wasmBytes)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load module: %w", err)
 	}
 
 	store := wasmtime.NewStore(engine)
+	// A fresh Store's epoch deadline defaults to zero, which traps on the
+	// very first epoch check - arm an effectively-infinite one up front so
+	// calls that reach into WASM outside callWithDeadline (e.g. alloc_string
+	// from WriteString) don't spuriously fail before any real deadline is
+	// ever set.
+	store.SetEpochDeadline(noEpochDeadlineTicks)
 	instance, err := wasmtime.NewInstance(store, module, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to instantiate module: %w", err)
@@ -173,26 +396,158 @@ func LoadCassette(path string, debug bool) (*Cassette, error) {
 		}
 	}
 
-	return &Cassette{
+	dedupStore := cfg.dedupStore
+	if dedupStore == nil {
+		dedupStore = NewMemoryDedupStore(defaultMaxEventsPerSubscription)
+	}
+
+	c := &Cassette{
 		engine:       engine,
 		store:        store,
 		instance:     instance,
 		memory:       memMgr,
-		eventTracker: NewEventTracker(),
+		dedupStore:   dedupStore,
+		cassetteHash: cassetteHash,
 		exports:      exports,
 		debug:        debug,
-	}, nil
+	}
+	c.epochStop = startEpochTicker(engine)
+	return c, nil
+}
+
+// startEpochTicker launches a background goroutine that increments engine's
+// epoch counter on a fixed interval, driving epoch-deadline interruption for
+// the Store built on it. Call the returned stop function to end it.
+func startEpochTicker(engine *wasmtime.Engine) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(epochTickInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				engine.IncrementEpoch()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// Close stops the cassette's background epoch ticker. It's safe to call
+// more than once.
+func (c *Cassette) Close() {
+	if c.epochStop != nil {
+		c.epochStop()
+		c.epochStop = nil
+	}
+}
+
+// effectiveDeadline reports ctx's deadline, if it has one.
+func effectiveDeadline(ctx context.Context) (time.Time, bool) {
+	return ctx.Deadline()
+}
+
+// epochTicksUntil converts a deadline into a tick count suitable for
+// Store.SetEpochDeadline, given the background ticker's interval.
+func epochTicksUntil(deadline time.Time) uint64 {
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return 1
+	}
+	return uint64(remaining/epochTickInterval) + 1
+}
+
+// callOutcome carries a WASM call's result back from the goroutine running
+// it to callWithDeadline's select.
+type callOutcome struct {
+	result interface{}
+	err    error
+}
+
+// isEpochTrap reports whether err looks like a wasmtime epoch-interruption
+// trap, as opposed to a guest-raised error.
+func isEpochTrap(err error) bool {
+	var trap *wasmtime.Trap
+	if !errors.As(err, &trap) {
+		return false
+	}
+	code := trap.Code()
+	return code != nil && *code == wasmtime.Interrupt
+}
+
+// callWithDeadline invokes fn.Call(c.store, args...) bounded by ctx. If ctx
+// carries no deadline and can't be cancelled, fn is called directly with no
+// extra bookkeeping. Otherwise the store's epoch deadline is armed, and if
+// ctx fires before the guest returns, the epoch deadline is forced to zero
+// so wasmtime traps the in-flight call; the trap is translated into an
+// error wrapping ErrCassetteTimeout and ctx.Err().
+func (c *Cassette) callWithDeadline(ctx context.Context, fn *wasmtime.Func, args ...interface{}) (interface{}, error) {
+	if ctx.Done() == nil {
+		// No deadline to enforce, but the store's epoch deadline still
+		// defaults to zero - arm an effectively-infinite one so this call
+		// doesn't trap on its first epoch check.
+		c.store.SetEpochDeadline(noEpochDeadlineTicks)
+		return fn.Call(c.store, args...)
+	}
+
+	if deadline, ok := effectiveDeadline(ctx); ok {
+		c.store.SetEpochDeadline(epochTicksUntil(deadline))
+	} else {
+		// No deadline on ctx, just cancellation - don't let the ticker trip
+		// this call on its own; only the ctx.Done() branch below should.
+		c.store.SetEpochDeadline(noEpochDeadlineTicks)
+	}
+
+	done := make(chan callOutcome, 1)
+	go func() {
+		result, err := fn.Call(c.store, args...)
+		done <- callOutcome{result, err}
+	}()
+
+	select {
+	case out := <-done:
+		return out.result, out.err
+	case <-ctx.Done():
+		out := c.forceTrapAndAwait(done)
+		if isEpochTrap(out.err) {
+			return nil, fmt.Errorf("%w: %v", ErrCassetteTimeout, ctx.Err())
+		}
+		return out.result, out.err
+	}
+}
+
+// forceTrapAndAwait forces wasmtime to trap the in-flight call by zeroing
+// the store's epoch deadline, then waits for the goroutine in
+// callWithDeadline to observe the trap and report outcome on done. The
+// deadline is re-armed to noEpochDeadlineTicks afterward - it only needs to
+// reach the in-flight call, and leaving it at zero would trap every later
+// call on this store too (e.g. Send's deferred dealloc_string).
+func (c *Cassette) forceTrapAndAwait(done <-chan callOutcome) callOutcome {
+	c.store.SetEpochDeadline(0)
+	out := <-done
+	c.store.SetEpochDeadline(noEpochDeadlineTicks)
+	return out
 }
 
 // Describe returns the cassette description
 func (c *Cassette) Describe() (string, error) {
+	return c.DescribeContext(context.Background())
+}
+
+// DescribeContext is like Describe but bounded by ctx: if ctx is cancelled
+// or its deadline elapses before the guest returns, the call is interrupted
+// via wasmtime epoch deadlines and an error wrapping ErrCassetteTimeout is
+// returned.
+func (c *Cassette) DescribeContext(ctx context.Context) (string, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	// First check if there's a describe function
 	descFunc, hasDescribe := c.exports["describe"]
 	if hasDescribe {
-		result, err := descFunc.Call(c.store)
+		result, err := c.callWithDeadline(ctx, descFunc)
 		if err != nil {
 			return "", err
 		}
@@ -217,7 +572,7 @@ func (c *Cassette) Describe() (string, error) {
 		return "Cassette with no description", nil
 	}
 
-	result, err := infoFunc.Call(c.store)
+	result, err := c.callWithDeadline(ctx, infoFunc)
 	if err != nil {
 		return "", err
 	}
@@ -270,30 +625,124 @@ func (c *Cassette) Describe() (string, error) {
 	return "Cassette with no description", nil
 }
 
+// RelayFrame is a typed view of one NIP-01 relay message emitted by
+// SendStream, so callers (e.g. a WebSocket handler) don't have to re-parse
+// raw JSON strings to dispatch on message type.
+type RelayFrame struct {
+	Type  string
+	SubID string
+	Event json.RawMessage
+	Raw   []byte
+}
+
+// parseRelayFrame parses one NIP-01 relay message line into a RelayFrame.
+func parseRelayFrame(line string) (RelayFrame, bool) {
+	var parsed []interface{}
+	if err := json.Unmarshal([]byte(line), &parsed); err != nil || len(parsed) < 1 {
+		return RelayFrame{}, false
+	}
+
+	msgType, ok := parsed[0].(string)
+	if !ok {
+		return RelayFrame{}, false
+	}
+
+	frame := RelayFrame{Type: msgType, Raw: []byte(line)}
+	if len(parsed) >= 2 {
+		if subID, ok := parsed[1].(string); ok {
+			frame.SubID = subID
+		}
+	}
+	if msgType == "EVENT" && len(parsed) >= 3 {
+		if eventBytes, err := json.Marshal(parsed[2]); err == nil {
+			frame.Event = eventBytes
+		}
+	}
+
+	return frame, true
+}
+
+// SendStream is like SendContext but emits each resulting relay frame on
+// the returned channel as soon as it's parsed, instead of rejoining them
+// into one newline-separated string. This lets callers (e.g. a WebSocket
+// handler) forward frames one at a time without allocating the full joined
+// buffer, which matters for cassettes that return many historical events
+// for a single REQ. The underlying WASM `send` call is still made exactly
+// once, through SendContext, which also applies the usual per-subscription
+// dedup and REQ/CLOSE bookkeeping; SendStream only changes how the result
+// is delivered. The channel closes once every frame has been sent, or
+// immediately if ctx is cancelled first.
+func (c *Cassette) SendStream(ctx context.Context, message string) (<-chan RelayFrame, error) {
+	resultStr, err := c.SendContext(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan RelayFrame, 16)
+	go func() {
+		defer close(out)
+
+		if resultStr == "" {
+			return
+		}
+
+		lines := []string{resultStr}
+		if strings.Contains(resultStr, "\n") {
+			lines = strings.Split(strings.TrimSpace(resultStr), "\n")
+		}
+
+		for _, line := range lines {
+			frame, ok := parseRelayFrame(line)
+			if !ok {
+				continue
+			}
+			select {
+			case out <- frame:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
 // Send processes any NIP-01 message
 func (c *Cassette) Send(message string) (string, error) {
+	return c.SendContext(context.Background(), message)
+}
+
+// SendContext is like Send but bounded by ctx: if ctx is cancelled or its
+// deadline elapses before the guest returns, the in-flight WASM call is
+// interrupted via wasmtime epoch deadlines and an error wrapping
+// ErrCassetteTimeout is returned. The message pointer written into guest
+// memory is always deallocated, even when the call is interrupted, so a
+// cancelled Send doesn't leak guest memory.
+func (c *Cassette) SendContext(ctx context.Context, message string) (string, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	// Parse message to check type
+	// Parse message to check type and subscription ID
 	var msgData []interface{}
-	if err := json.Unmarshal([]byte(message), &msgData); err == nil {
-		if len(msgData) >= 1 {
-			msgType, ok := msgData[0].(string)
-			if ok {
-				switch msgType {
-				case "REQ":
-					// New REQ, reset event tracker
-					c.eventTracker.Reset()
-					if c.debug {
-						fmt.Println("[Cassette] New REQ, resetting event tracker")
-					}
-				case "CLOSE":
-					// CLOSE message, reset event tracker for that subscription
-					c.eventTracker.Reset()
-					if c.debug {
-						fmt.Println("[Cassette] CLOSE message, resetting event tracker")
-					}
+	if err := json.Unmarshal([]byte(message), &msgData); err == nil && len(msgData) >= 2 {
+		if msgType, ok := msgData[0].(string); ok {
+			subID, _ := msgData[1].(string)
+			switch msgType {
+			case "REQ":
+				// New REQ, reset this subscription's dedup state
+				if err := c.dedupStore.Reset(subID); err != nil && c.debug {
+					fmt.Printf("[Cassette] Failed to reset dedup state for subscription %s: %v\n", subID, err)
+				}
+				if c.debug {
+					fmt.Printf("[Cassette] New REQ, resetting dedup state for subscription %s\n", subID)
+				}
+			case "CLOSE":
+				// CLOSE message, free this subscription's dedup state
+				if err := c.dedupStore.Reset(subID); err != nil && c.debug {
+					fmt.Printf("[Cassette] Failed to reset dedup state for subscription %s: %v\n", subID, err)
+				}
+				if c.debug {
+					fmt.Printf("[Cassette] CLOSE message, freeing dedup state for subscription %s\n", subID)
 				}
 			}
 		}
@@ -305,22 +754,25 @@ func (c *Cassette) Send(message string) (string, error) {
 		return "", err
 	}
 
+	// Deallocate the message pointer no matter how the call below ends, so
+	// a cancelled/timed-out Send doesn't leak guest memory.
+	defer func() {
+		if deallocFunc, ok := c.exports["dealloc_string"]; ok {
+			deallocFunc.Call(c.store, msgPtr, int32(len(message)))
+		}
+	}()
+
 	// Call send function
 	sendFunc, ok := c.exports["send"]
 	if !ok {
 		return "", fmt.Errorf("send function not found")
 	}
 
-	result, err := sendFunc.Call(c.store, msgPtr, int32(len(message)))
+	result, err := c.callWithDeadline(ctx, sendFunc, msgPtr, int32(len(message)))
 	if err != nil {
 		return "", err
 	}
 
-	// Deallocate message
-	if deallocFunc, ok := c.exports["dealloc_string"]; ok {
-		deallocFunc.Call(c.store, msgPtr, int32(len(message)))
-	}
-
 	resultPtr := result.(int32)
 	if resultPtr == 0 {
 		return `["NOTICE", "send() returned null pointer"]`, nil
@@ -376,13 +828,18 @@ func (c *Cassette) Send(message string) (string, error) {
 				continue
 			}
 
-			// Filter duplicate events
+			// Filter duplicate events, scoped to this message's own subscription
+			subID, _ := parsed[1].(string)
 			if msgType == "EVENT" && len(parsed) >= 3 {
 				if eventMap, ok := parsed[2].(map[string]interface{}); ok {
 					if eventID, ok := eventMap["id"].(string); ok {
-						if !c.eventTracker.AddAndCheck(eventID) {
+						seen, err := c.dedupStore.SeenAndMark(subID, eventID)
+						if err != nil && c.debug {
+							fmt.Printf("[Cassette] Dedup store error for event %s: %v\n", eventID, err)
+						}
+						if seen {
 							if c.debug {
-								fmt.Printf("[Cassette] Filtering duplicate event: %s\n", eventID)
+								fmt.Printf("[Cassette] Filtering duplicate event %s on subscription %s\n", eventID, subID)
 							}
 							continue
 						}
@@ -401,13 +858,18 @@ func (c *Cassette) Send(message string) (string, error) {
 
 	// Single message - check for duplicate
 	var parsed []interface{}
-	if err := json.Unmarshal([]byte(resultStr), &parsed); err == nil {
+	if err := json.Unmarshal([]byte(resultStr), &parsed); err == nil && len(parsed) >= 2 {
 		if len(parsed) >= 3 && parsed[0] == "EVENT" {
+			subID, _ := parsed[1].(string)
 			if eventMap, ok := parsed[2].(map[string]interface{}); ok {
 				if eventID, ok := eventMap["id"].(string); ok {
-					if !c.eventTracker.AddAndCheck(eventID) {
+					seen, err := c.dedupStore.SeenAndMark(subID, eventID)
+					if err != nil && c.debug {
+						fmt.Printf("[Cassette] Dedup store error for event %s: %v\n", eventID, err)
+					}
+					if seen {
 						if c.debug {
-							fmt.Printf("[Cassette] Filtering duplicate event: %s\n", eventID)
+							fmt.Printf("[Cassette] Filtering duplicate event %s on subscription %s\n", eventID, subID)
 						}
 						return "", nil
 					}
@@ -422,6 +884,12 @@ func (c *Cassette) Send(message string) (string, error) {
 
 // Info returns NIP-11 relay information
 func (c *Cassette) Info() (string, error) {
+	return c.InfoContext(context.Background())
+}
+
+// InfoContext is like Info but also bounded by ctx, per the same rules as
+// DescribeContext.
+func (c *Cassette) InfoContext(ctx context.Context) (string, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -432,7 +900,7 @@ func (c *Cassette) Info() (string, error) {
 	}
 
 	// Call info function
-	result, err := infoFunc.Call(c.store)
+	result, err := c.callWithDeadline(ctx, infoFunc)
 	if err != nil {
 		return "", err
 	}
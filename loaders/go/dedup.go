@@ -0,0 +1,165 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// DedupStore tracks which event IDs have already been delivered on a given
+// subscription, independent of where that state lives. The in-memory
+// EventTracker-backed implementation is the default; BoltDedupStore is a
+// persistent alternative for deployments that can't afford to re-emit every
+// historical event after a restart.
+type DedupStore interface {
+	// SeenAndMark reports whether eventID was already marked as seen for
+	// subID, then unconditionally (re)marks it as seen.
+	SeenAndMark(subID, eventID string) (bool, error)
+	// Reset clears dedup state for subID, called on REQ and CLOSE.
+	Reset(subID string) error
+}
+
+// loadConfig holds LoadCassette's optional settings.
+type loadConfig struct {
+	dedupStore DedupStore
+}
+
+// LoadOption configures LoadCassette.
+type LoadOption func(*loadConfig)
+
+// WithDedupStore overrides the default in-memory dedup backend with store,
+// e.g. a BoltDedupStore so dedup state survives process restarts.
+func WithDedupStore(store DedupStore) LoadOption {
+	return func(cfg *loadConfig) { cfg.dedupStore = store }
+}
+
+// MemoryDedupStore adapts an EventTracker to the DedupStore interface. It's
+// the default dedup backend and carries no state across restarts.
+type MemoryDedupStore struct {
+	tracker *EventTracker
+}
+
+// NewMemoryDedupStore creates a MemoryDedupStore whose subscriptions retain
+// at most maxEventsPerSubscription event IDs each (0 means unbounded).
+func NewMemoryDedupStore(maxEventsPerSubscription int) *MemoryDedupStore {
+	return &MemoryDedupStore{tracker: NewEventTracker(maxEventsPerSubscription)}
+}
+
+// SeenAndMark implements DedupStore.
+func (s *MemoryDedupStore) SeenAndMark(subID, eventID string) (bool, error) {
+	isNew := s.tracker.AddAndCheck(subID, eventID)
+	return !isNew, nil
+}
+
+// Reset implements DedupStore.
+func (s *MemoryDedupStore) Reset(subID string) error {
+	s.tracker.ResetSub(subID)
+	return nil
+}
+
+// boltDedupEntry is the persisted value for one dedup key in BoltDedupStore.
+type boltDedupEntry struct {
+	SeenAt int64 `json:"seen_at"`
+}
+
+// boltDedupBucket is the single bbolt bucket BoltDedupStore keeps its
+// entries in.
+var boltDedupBucket = []byte("cassette_dedup")
+
+// BoltDedupStore is a DedupStore backed by an embedded bbolt database, so
+// dedup state survives process restarts. Keys are namespaced by the
+// cassetteHash given to NewBoltDedupStore, so one bbolt file can back
+// multiple cassettes without their event IDs colliding as long as each
+// store gets a distinct hash. LoadCassette computes a SHA-256 of the module
+// bytes for its Cassette but does not pass it to the dedup store; the hash
+// used here is whatever the caller supplied.
+type BoltDedupStore struct {
+	db           *bolt.DB
+	cassetteHash string
+	ttl          time.Duration
+}
+
+// NewBoltDedupStore opens (creating if needed) a bbolt database at path and
+// returns a DedupStore scoped to cassetteHash. Entries older than ttl are
+// treated as unseen; ttl <= 0 disables expiry.
+func NewBoltDedupStore(path string, cassetteHash string, ttl time.Duration) (*BoltDedupStore, error) {
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dedup store %s: %w", path, err)
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltDedupBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to init dedup bucket: %w", err)
+	}
+
+	return &BoltDedupStore{db: db, cassetteHash: cassetteHash, ttl: ttl}, nil
+}
+
+// key builds the <cassette-hash>:<subID>:<eventID> key a dedup entry is
+// stored under.
+func (s *BoltDedupStore) key(subID, eventID string) []byte {
+	return []byte(fmt.Sprintf("%s:%s:%s", s.cassetteHash, subID, eventID))
+}
+
+// SeenAndMark implements DedupStore.
+func (s *BoltDedupStore) SeenAndMark(subID, eventID string) (bool, error) {
+	key := s.key(subID, eventID)
+	seen := false
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltDedupBucket)
+
+		if data := b.Get(key); data != nil {
+			var entry boltDedupEntry
+			if err := json.Unmarshal(data, &entry); err == nil {
+				if s.ttl <= 0 || time.Since(time.Unix(entry.SeenAt, 0)) < s.ttl {
+					seen = true
+				}
+			}
+		}
+
+		data, err := json.Marshal(boltDedupEntry{SeenAt: time.Now().Unix()})
+		if err != nil {
+			return err
+		}
+		return b.Put(key, data)
+	})
+	if err != nil {
+		return false, err
+	}
+	return seen, nil
+}
+
+// Reset implements DedupStore. bbolt has no prefix-delete, so matching keys
+// are found with a cursor scan before being removed.
+func (s *BoltDedupStore) Reset(subID string) error {
+	prefix := []byte(fmt.Sprintf("%s:%s:", s.cassetteHash, subID))
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltDedupBucket)
+		c := b.Cursor()
+
+		var keys [][]byte
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			keys = append(keys, append([]byte(nil), k...))
+		}
+		for _, k := range keys {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Close closes the underlying bbolt database.
+func (s *BoltDedupStore) Close() error {
+	return s.db.Close()
+}